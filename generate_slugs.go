@@ -0,0 +1,18 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"blog-service/internal/service"
+)
+
+func runGenerateSlugs() error {
+	svc := service.New(context.Background())
+	articles, tags, err := svc.GenerateSlugs()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("generated slugs for %d articles and %d tags\n", articles, tags)
+	return nil
+}
@@ -0,0 +1,7 @@
+package global
+
+import "blog-service/pkg/search"
+
+var (
+	SearchIndex *search.Index
+)
@@ -1,13 +1,23 @@
 package global
 
 import (
+	"sync/atomic"
+
 	"blog-service/pkg/logger"
 	"blog-service/pkg/setting"
 )
 
+// 配置文件变化时整体替换为新的配置，通过 Load 读取，同一次处理中多次使用时只 Load 一次
 var (
-	ServerSetting   *setting.ServerSettingS //ServerSettingS 结构体指针
-	AppSetting      *setting.AppSettingS
-	DatabaseSetting *setting.DatabaseSettingS
-	Logger          *logger.Logger
+	ServerSetting    atomic.Pointer[setting.ServerSettingS]
+	AppSetting       atomic.Pointer[setting.AppSettingS]
+	LogSetting       atomic.Pointer[setting.LogSettingS]
+	DatabaseSetting  atomic.Pointer[setting.DatabaseSettingS]
+	JWTSetting       atomic.Pointer[setting.JWTSettingS]
+	LimiterSetting   atomic.Pointer[setting.LimiterSettingS]
+	SiteSetting      atomic.Pointer[setting.SiteSettingS]
+	SearchSetting    atomic.Pointer[setting.SearchSettingS]
+	SchedulerSetting atomic.Pointer[setting.SchedulerSettingS]
 )
+
+var Logger *logger.Logger
@@ -0,0 +1,145 @@
+package dao
+
+import (
+	"blog-service/internal/model"
+	"blog-service/pkg/app"
+)
+
+type Article struct {
+	ID            uint32 `json:"id"`
+	Title         string `json:"title"`
+	Slug          string `json:"slug"`
+	Desc          string `json:"desc"`
+	Content       string `json:"content"`
+	ContentHTML   string `json:"content_html"`
+	ContentTOC    string `json:"content_toc"`
+	CoverImageUrl string `json:"cover_image_url"`
+	CreatedBy     string `json:"created_by"`
+	ModifiedBy    string `json:"modified_by"`
+	State         uint8  `json:"state"`
+	PublishAt     uint32 `json:"publish_at"`
+}
+
+func (d *Dao) CreateArticle(param *Article) (*model.Article, error) {
+	article := model.Article{
+		Title:         param.Title,
+		Slug:          param.Slug,
+		Desc:          param.Desc,
+		Content:       param.Content,
+		ContentHTML:   param.ContentHTML,
+		ContentTOC:    param.ContentTOC,
+		CoverImageUrl: param.CoverImageUrl,
+		State:         param.State,
+		PublishAt:     param.PublishAt,
+		Model:         &model.Model{CreatedBy: param.CreatedBy},
+	}
+	return article.Create(d.engine)
+}
+
+func (d *Dao) UpdateArticle(param *Article) error {
+	article := model.Article{Model: &model.Model{ID: param.ID}}
+	// 状态通过 TransitionArticle 修改
+	values := map[string]interface{}{
+		"modified_by": param.ModifiedBy,
+	}
+	if param.Title != "" {
+		values["title"] = param.Title
+	}
+	if param.Slug != "" {
+		values["slug"] = param.Slug
+	}
+	if param.CoverImageUrl != "" {
+		values["cover_image_url"] = param.CoverImageUrl
+	}
+	if param.Desc != "" {
+		values["desc"] = param.Desc
+	}
+	if param.Content != "" {
+		values["content"] = param.Content
+		values["content_html"] = param.ContentHTML
+		values["content_toc"] = param.ContentTOC
+	}
+	return article.Update(d.engine, values)
+}
+
+// 只更新渲染结果
+func (d *Dao) UpdateArticleContentHTML(id uint32, contentHTML, contentTOC string) error {
+	article := model.Article{Model: &model.Model{ID: id}}
+	values := map[string]interface{}{
+		"content_html": contentHTML,
+		"content_toc":  contentTOC,
+	}
+	return article.Update(d.engine, values)
+}
+
+func (d *Dao) GetArticlesAfterID(afterID uint32, limit int) ([]*model.Article, error) {
+	return model.Article{}.ListAfterID(d.engine, afterID, limit)
+}
+
+func (d *Dao) TransitionArticle(id uint32, from, to uint8, publishAt uint32, modifiedBy string) (bool, error) {
+	article := model.Article{Model: &model.Model{ID: id}}
+	values := map[string]interface{}{
+		"publish_at":  publishAt,
+		"modified_by": modifiedBy,
+	}
+	return article.Transition(d.engine, from, to, values)
+}
+
+func (d *Dao) GetDueArticles(now uint32, limit int) ([]*model.Article, error) {
+	return model.Article{}.ListDue(d.engine, now, limit)
+}
+
+func (d *Dao) GetArticle(id uint32, state uint8) (model.Article, error) {
+	article := model.Article{Model: &model.Model{ID: id}, State: state}
+	return article.Get(d.engine)
+}
+
+func (d *Dao) GetArticleBySlug(slug string, state uint8) (model.Article, error) {
+	article := model.Article{Slug: slug, State: state}
+	return article.GetBySlug(d.engine)
+}
+
+func (d *Dao) GetArticleByID(id uint32) (model.Article, error) {
+	article := model.Article{Model: &model.Model{ID: id}}
+	return article.GetByID(d.engine)
+}
+
+func (d *Dao) DeleteArticle(id uint32) error {
+	article := model.Article{Model: &model.Model{ID: id}}
+	return article.Delete(d.engine)
+}
+
+func (d *Dao) CountArticleList(tagID uint32, state uint8, createdBy string) (int64, error) {
+	article := model.Article{Model: &model.Model{CreatedBy: createdBy}, State: state}
+	return article.Count(d.engine, tagID)
+}
+
+func (d *Dao) GetArticleList(tagID uint32, state uint8, createdBy string, page, pageSize int) ([]*model.Article, error) {
+	article := model.Article{Model: &model.Model{CreatedBy: createdBy}, State: state}
+	return article.List(d.engine, tagID, app.GetPageOffset(page, pageSize), pageSize)
+}
+
+func (d *Dao) GetArticleListByCursor(tagID uint32, state uint8, createdBy string, cursor *app.Cursor, limit int) ([]*model.Article, error) {
+	article := model.Article{Model: &model.Model{CreatedBy: createdBy}, State: state}
+	return article.ListByCursor(d.engine, tagID, cursor, limit)
+}
+
+func (d *Dao) GetLatestArticles(tagID uint32, state uint8, limit int) ([]*model.Article, error) {
+	article := model.Article{State: state}
+	return article.ListLatest(d.engine, tagID, limit)
+}
+
+func (d *Dao) GetArticleStat(tagID uint32, state uint8) (model.ArticleStat, error) {
+	article := model.Article{State: state}
+	return article.Stat(d.engine, tagID)
+}
+
+func (d *Dao) GetArticleModifiedList(state uint8) ([]*model.ModifiedRow, error) {
+	article := model.Article{State: state}
+	return article.ListModified(d.engine)
+}
+
+func (d *Dao) GetArticlesByIDs(ids []uint32, state uint8) ([]*model.Article, error) {
+	article := model.Article{State: state}
+	return article.ListByIDs(d.engine, ids)
+}
@@ -0,0 +1,62 @@
+package dao
+
+import (
+	"blog-service/internal/model"
+	"blog-service/pkg/app"
+)
+
+// 以文章当前内容保存一个修订
+func (d *Dao) CreateArticleRevision(article *model.Article, tagIDs []uint32, createdBy string) (*model.ArticleRevision, error) {
+	state := article.State
+	revision := model.ArticleRevision{
+		ArticleID:     article.ID,
+		Title:         article.Title,
+		Desc:          article.Desc,
+		Content:       article.Content,
+		CoverImageUrl: article.CoverImageUrl,
+		State:         &state,
+		TagIDs:        model.JoinTagIDs(tagIDs),
+		Model:         &model.Model{CreatedBy: createdBy},
+	}
+	return revision.Create(d.engine)
+}
+
+func (d *Dao) GetArticleRevision(articleID, id uint32) (model.ArticleRevision, error) {
+	revision := model.ArticleRevision{Model: &model.Model{ID: id}, ArticleID: articleID}
+	return revision.Get(d.engine)
+}
+
+func (d *Dao) GetPreviousArticleRevision(articleID, id uint32) (model.ArticleRevision, error) {
+	revision := model.ArticleRevision{Model: &model.Model{ID: id}, ArticleID: articleID}
+	return revision.Previous(d.engine)
+}
+
+func (d *Dao) CountArticleRevision(articleID uint32) (int64, error) {
+	revision := model.ArticleRevision{ArticleID: articleID}
+	return revision.Count(d.engine)
+}
+
+func (d *Dao) GetArticleRevisionList(articleID uint32, page, pageSize int) ([]*model.ArticleRevision, error) {
+	revision := model.ArticleRevision{ArticleID: articleID}
+	return revision.List(d.engine, app.GetPageOffset(page, pageSize), pageSize)
+}
+
+// 用修订的内容覆盖文章的标题、简述和正文，空值同样写入，封面为空时保持不变
+func (d *Dao) RestoreArticle(param *Article) error {
+	article := model.Article{Model: &model.Model{ID: param.ID}}
+	values := map[string]interface{}{
+		"title":        param.Title,
+		"desc":         param.Desc,
+		"content":      param.Content,
+		"content_html": param.ContentHTML,
+		"content_toc":  param.ContentTOC,
+		"modified_by":  param.ModifiedBy,
+	}
+	if param.Slug != "" {
+		values["slug"] = param.Slug
+	}
+	if param.CoverImageUrl != "" {
+		values["cover_image_url"] = param.CoverImageUrl
+	}
+	return article.Update(d.engine, values)
+}
@@ -0,0 +1,40 @@
+package dao
+
+import "blog-service/internal/model"
+
+func (d *Dao) GetArticleTagsByAIDs(articleIDs []uint32) ([]*model.ArticleTagRow, error) {
+	articleTag := model.ArticleTag{}
+	return articleTag.ListTagsByAIDs(d.engine, articleIDs)
+}
+
+// 用 tagIDs 整体替换文章的标签集合，调用方需在事务中执行
+func (d *Dao) ReplaceArticleTags(articleID uint32, tagIDs []uint32, operator string) error {
+	articleTag := model.ArticleTag{ArticleID: articleID}
+	if err := articleTag.DeleteByAID(d.engine); err != nil {
+		return err
+	}
+	articleTags := make([]*model.ArticleTag, 0, len(tagIDs))
+	for _, tagID := range tagIDs {
+		articleTags = append(articleTags, &model.ArticleTag{
+			Model:     &model.Model{CreatedBy: operator},
+			ArticleID: articleID,
+			TagID:     tagID,
+		})
+	}
+	return articleTag.CreateBatch(d.engine, articleTags)
+}
+
+func (d *Dao) DeleteArticleTags(articleID uint32) error {
+	articleTag := model.ArticleTag{ArticleID: articleID}
+	return articleTag.DeleteByAID(d.engine)
+}
+
+func (d *Dao) GetTagArticleIDs(tagID uint32) ([]uint32, error) {
+	articleTag := model.ArticleTag{TagID: tagID}
+	return articleTag.ListAIDsByTID(d.engine)
+}
+
+func (d *Dao) DeleteTagArticles(tagID uint32) error {
+	articleTag := model.ArticleTag{TagID: tagID}
+	return articleTag.DeleteByTID(d.engine)
+}
@@ -0,0 +1,13 @@
+package dao
+
+import "blog-service/internal/model"
+
+func (d *Dao) GetAuth(appKey, appSecret string) (model.Auth, error) {
+	auth := model.Auth{AppKey: appKey, AppSecret: appSecret}
+	return auth.Get(d.engine)
+}
+
+func (d *Dao) AppKeyExists(appKey string) (bool, error) {
+	auth := model.Auth{AppKey: appKey}
+	return auth.Exists(d.engine)
+}
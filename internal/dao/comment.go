@@ -0,0 +1,57 @@
+package dao
+
+import (
+	"blog-service/internal/model"
+	"blog-service/pkg/app"
+)
+
+type Comment struct {
+	ArticleID uint32
+	ParentID  uint32
+	Author    string
+	Email     string
+	Content   string
+	State     uint8
+}
+
+func (d *Dao) CreateComment(param *Comment) (*model.Comment, error) {
+	comment := model.Comment{
+		ArticleID: param.ArticleID,
+		ParentID:  param.ParentID,
+		Author:    param.Author,
+		Email:     param.Email,
+		Content:   param.Content,
+		State:     param.State,
+		Model:     &model.Model{CreatedBy: param.Author},
+	}
+	return comment.Create(d.engine)
+}
+
+func (d *Dao) GetComment(id uint32) (model.Comment, error) {
+	comment := model.Comment{Model: &model.Model{ID: id}}
+	return comment.Get(d.engine)
+}
+
+func (d *Dao) UpdateCommentState(id uint32, state uint8, modifiedBy string) error {
+	comment := model.Comment{Model: &model.Model{ID: id}}
+	values := map[string]interface{}{
+		"state":       state,
+		"modified_by": modifiedBy,
+	}
+	return comment.UpdateState(d.engine, values)
+}
+
+func (d *Dao) CountComment(articleID uint32, state uint8) (int64, error) {
+	comment := model.Comment{ArticleID: articleID, State: state}
+	return comment.Count(d.engine)
+}
+
+func (d *Dao) GetCommentList(articleID uint32, state uint8, page, pageSize int) ([]*model.Comment, error) {
+	comment := model.Comment{ArticleID: articleID, State: state}
+	return comment.List(d.engine, app.GetPageOffset(page, pageSize), pageSize)
+}
+
+func (d *Dao) DeleteArticleComments(articleID uint32) error {
+	comment := model.Comment{ArticleID: articleID}
+	return comment.DeleteByAID(d.engine)
+}
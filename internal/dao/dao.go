@@ -9,3 +9,10 @@ type Dao struct {
 func New(engine *gorm.DB) *Dao {
 	return &Dao{engine: engine}
 }
+
+// 在同一个数据库事务中执行 fn，fn 返回错误时回滚
+func (d *Dao) Transaction(fn func(tx *Dao) error) error {
+	return d.engine.Transaction(func(tx *gorm.DB) error {
+		return fn(New(tx))
+	})
+}
@@ -0,0 +1,27 @@
+package dao
+
+import "blog-service/internal/model"
+
+func (d *Dao) IsSlugTaken(kind, slug string, excludeID uint32) (bool, error) {
+	return model.SlugTaken(d.engine, kind, slug, excludeID)
+}
+
+func (d *Dao) CreateSlugRedirect(kind, oldSlug string, targetID uint32, createdBy string) error {
+	redirect := model.SlugRedirect{
+		Kind:     kind,
+		OldSlug:  oldSlug,
+		TargetID: targetID,
+		Model:    &model.Model{CreatedBy: createdBy},
+	}
+	return redirect.Create(d.engine)
+}
+
+func (d *Dao) GetSlugRedirect(kind, oldSlug string) (model.SlugRedirect, error) {
+	redirect := model.SlugRedirect{Kind: kind, OldSlug: oldSlug}
+	return redirect.Get(d.engine)
+}
+
+func (d *Dao) DeleteSlugRedirect(kind, oldSlug string, targetID uint32) error {
+	redirect := model.SlugRedirect{Kind: kind, OldSlug: oldSlug, TargetID: targetID}
+	return redirect.DeleteByTarget(d.engine)
+}
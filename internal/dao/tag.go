@@ -11,18 +11,26 @@ func (d *Dao) GetTagList(name string, state uint8, page, pageSize int) ([]*model
 	return tag.List(d.engine, pageOffset, pageSize)
 }
 
-func (d *Dao) CreateTag(name string, state uint8, createdBy string) error {
+func (d *Dao) GetTagListByCursor(name string, state uint8, cursor *app.Cursor, limit int) ([]*model.Tag, error) {
+	tag := model.Tag{Name: name, State: state}
+	return tag.ListByCursor(d.engine, cursor, limit)
+}
+
+func (d *Dao) CreateTag(name, slug string, state uint8, createdBy string) error {
 	tag := model.Tag{
 		Name:  name,
+		Slug:  slug,
 		State: state,
 		Model: &model.Model{CreatedBy: createdBy},
 	}
 	return tag.Create(d.engine)
 }
 
-func (d *Dao) UpdateTag(id uint32, name string, state uint8, modifiedBy string) error {
+// name、slug 为空时不修改
+func (d *Dao) UpdateTag(id uint32, name, slug string, state uint8, modifiedBy string) error {
 	tag := model.Tag{
 		Name:  name,
+		Slug:  slug,
 		State: state,
 		Model: &model.Model{
 			ID:         id,
@@ -37,7 +45,37 @@ func (d *Dao) DeleteTag(id uint32) error {
 	return tag.Delete(d.engine)
 }
 
+// tagIDs 不能有重复
+func (d *Dao) CountOpenTags(tagIDs []uint32) (int64, error) {
+	tag := model.Tag{State: model.STATE_OPEN}
+	return tag.CountByIDs(d.engine, tagIDs)
+}
+
 func (d *Dao) CountTag(name string, state uint8) (int64, error) {
 	tag := model.Tag{Name: name, State: state}
 	return tag.Count(d.engine)
 }
+
+func (d *Dao) GetTag(id uint32, state uint8) (model.Tag, error) {
+	tag := model.Tag{Model: &model.Model{ID: id}, State: state}
+	return tag.Get(d.engine)
+}
+
+func (d *Dao) GetTagByID(id uint32) (model.Tag, error) {
+	tag := model.Tag{Model: &model.Model{ID: id}}
+	return tag.GetByID(d.engine)
+}
+
+func (d *Dao) GetTagBySlug(slug string, state uint8) (model.Tag, error) {
+	tag := model.Tag{Slug: slug, State: state}
+	return tag.GetBySlug(d.engine)
+}
+
+func (d *Dao) GetTagsAfterID(afterID uint32, limit int) ([]*model.Tag, error) {
+	return model.Tag{}.ListAfterID(d.engine, afterID, limit)
+}
+
+func (d *Dao) GetTagModifiedList(state uint8) ([]*model.ModifiedRow, error) {
+	tag := model.Tag{State: state}
+	return tag.ListModified(d.engine)
+}
@@ -0,0 +1,65 @@
+package dao
+
+import (
+	"blog-service/internal/model"
+	"blog-service/pkg/app"
+)
+
+type User struct {
+	ID         uint32
+	Username   string
+	Password   string
+	Role       string
+	State      *uint8
+	CreatedBy  string
+	ModifiedBy string
+}
+
+func (d *Dao) CreateUser(param *User) (*model.User, error) {
+	user := model.User{
+		Username: param.Username,
+		Password: param.Password,
+		Role:     param.Role,
+		State:    model.STATE_OPEN,
+		Model:    &model.Model{CreatedBy: param.CreatedBy},
+	}
+	return user.Create(d.engine)
+}
+
+// Password、Role 为空及 State 为 nil 时不更新对应字段
+func (d *Dao) UpdateUser(param *User) error {
+	user := model.User{Model: &model.Model{ID: param.ID}}
+	values := map[string]interface{}{
+		"modified_by": param.ModifiedBy,
+	}
+	if param.Password != "" {
+		values["password"] = param.Password
+	}
+	if param.Role != "" {
+		values["role"] = param.Role
+	}
+	if param.State != nil {
+		values["state"] = *param.State
+	}
+	return user.Update(d.engine, values)
+}
+
+func (d *Dao) GetUser(id uint32) (model.User, error) {
+	user := model.User{Model: &model.Model{ID: id}}
+	return user.Get(d.engine)
+}
+
+func (d *Dao) GetUserByUsername(username string) (model.User, error) {
+	user := model.User{Username: username}
+	return user.GetByUsername(d.engine)
+}
+
+func (d *Dao) CountUser(role string) (int64, error) {
+	user := model.User{Role: role}
+	return user.Count(d.engine)
+}
+
+func (d *Dao) GetUserList(role string, page, pageSize int) ([]*model.User, error) {
+	user := model.User{Role: role}
+	return user.List(d.engine, app.GetPageOffset(page, pageSize), pageSize)
+}
@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"strings"
+	"time"
+
+	"blog-service/global"
+	"blog-service/pkg/app"
+	"blog-service/pkg/logger"
+	"github.com/gin-gonic/gin"
+)
+
+// 每个请求结束后通过 global.Logger 输出一条 JSON 访问日志，skipPaths 中的路径前缀不记录
+func AccessLog(skipPaths []string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		path := c.Request.URL.Path
+		for _, p := range skipPaths {
+			if strings.HasPrefix(path, p) {
+				c.Next()
+				return
+			}
+		}
+
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = path
+		}
+		status := c.Writer.Status()
+		size := c.Writer.Size()
+		if size < 0 {
+			size = 0
+		}
+		fields := logger.Filelds{
+			"method":     c.Request.Method,
+			"path":       route,
+			"status":     status,
+			"latency_ms": float64(time.Since(start).Microseconds()) / 1000,
+			"client_ip":  c.ClientIP(),
+			"bytes":      size,
+			"request_id": c.GetString("request_id"),
+		}
+		if code, ok := app.GetErrCode(c); ok {
+			fields["errcode"] = code
+		}
+		l := global.Logger.WithContext(c.Request.Context()).WithFields(fields)
+		switch {
+		case status >= 500:
+			l.Error("access")
+		case status >= 400:
+			l.Warn("access")
+		default:
+			l.Info("access")
+		}
+	}
+}
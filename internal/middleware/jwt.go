@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"errors"
+	"strings"
+
+	"blog-service/global"
+	"blog-service/internal/service"
+	"blog-service/pkg/app"
+	"blog-service/pkg/errcode"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// 校验请求携带的 Token，支持 query/header 中的 token 以及 Authorization: Bearer
+func JWTAuth() gin.HandlerFunc {
+	return jwtAuth(false)
+}
+
+// 携带 Token 时与 JWTAuth 相同，未携带时以匿名身份继续
+// 用于公开的读接口，有权限的身份可以读到更多内容
+func OptionalJWTAuth() gin.HandlerFunc {
+	return jwtAuth(true)
+}
+
+func jwtAuth(optional bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ecode = errcode.Success
+		token := getToken(c)
+		if token == "" {
+			if optional {
+				c.Next()
+				return
+			}
+			ecode = errcode.UnauthorizedTokenError.WithDetails("token is required")
+		} else {
+			claims, err := app.ParseToken(token)
+			switch {
+			case err == nil:
+				ecode = setPrincipal(c, claims)
+			case errors.Is(err, jwt.ErrTokenExpired):
+				ecode = errcode.UnauthorizedTokenTimeout
+			default:
+				ecode = errcode.UnauthorizedTokenError
+			}
+		}
+		if ecode != errcode.Success {
+			app.NewResponse(c).ToErrorResponse(ecode)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// 写入请求的 context，service 层据此校验权限并填写 CreatedBy/ModifiedBy
+// 用户的角色和状态按数据库重新加载，停用或变更角色后旧 Token 立即失效或降权，删除的 AppKey 同样立即失效
+func setPrincipal(c *gin.Context, claims *app.Claims) *errcode.Error {
+	svc := service.New(c.Request.Context())
+	principal, err := svc.ReloadPrincipal(claims.Principal())
+	if errors.Is(err, service.ErrUserDisabled) || errors.Is(err, service.ErrAppKeyRevoked) {
+		return errcode.UnauthorizedTokenError.WithDetails(err.Error())
+	}
+	if err != nil {
+		global.Logger.WithContext(c.Request.Context()).Errorf("svc.ReloadPrincipal err: %v", err)
+		return errcode.ServerError
+	}
+	c.Set("app_key", claims.AppKey)
+	c.Request = c.Request.WithContext(app.NewPrincipalContext(c.Request.Context(), principal))
+	return errcode.Success
+}
+
+func getToken(c *gin.Context) string {
+	if s, exist := c.GetQuery("token"); exist {
+		return s
+	}
+	if s := c.GetHeader("token"); s != "" {
+		return s
+	}
+	if s := c.GetHeader("Authorization"); strings.HasPrefix(s, "Bearer ") {
+		return strings.TrimPrefix(s, "Bearer ")
+	}
+	return ""
+}
@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"math"
+	"strconv"
+	"time"
+
+	"blog-service/pkg/app"
+	"blog-service/pkg/errcode"
+	"blog-service/pkg/limiter"
+	"github.com/gin-gonic/gin"
+)
+
+// 按路由模板和客户端 IP 限流，超出时返回 errcode.TooManyRequests
+func RateLimiter(l limiter.LimiterIface) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		route := c.FullPath()
+		if route == "" {
+			c.Next()
+			return
+		}
+		result, ok := l.Allow(route, c.ClientIP())
+		if !ok {
+			c.Next()
+			return
+		}
+		c.Header("X-RateLimit-Limit", strconv.FormatInt(result.Limit, 10))
+		c.Header("X-RateLimit-Remaining", strconv.FormatInt(result.Remaining, 10))
+		c.Header("X-RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset)))
+		if !result.Allowed {
+			c.Header("Retry-After", strconv.Itoa(ceilSeconds(result.RetryAfter)))
+			app.NewResponse(c).ToErrorResponse(errcode.TooManyRequests)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"blog-service/pkg/app"
+	"blog-service/pkg/metrics"
+	"github.com/gin-gonic/gin"
+)
+
+// 记录每个请求的次数、耗时以及返回的 errcode 错误码
+func Metrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			// 未匹配的路由统一归类，避免标签基数失控
+			route = "unmatched"
+		}
+		method := c.Request.Method
+		metrics.RequestsTotal.WithLabelValues(method, route, strconv.Itoa(c.Writer.Status())).Inc()
+		metrics.RequestDuration.WithLabelValues(method, route).Observe(time.Since(start).Seconds())
+		if code, ok := app.GetErrCode(c); ok {
+			metrics.ErrorsTotal.WithLabelValues(method, route, strconv.Itoa(code)).Inc()
+		}
+	}
+}
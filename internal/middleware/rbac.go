@@ -0,0 +1,20 @@
+package middleware
+
+import (
+	"blog-service/pkg/app"
+	"blog-service/pkg/errcode"
+	"github.com/gin-gonic/gin"
+)
+
+// 要求当前身份拥有 perms 中任意一个权限，需要放在 JWTAuth 之后
+// 只能编辑自己文章这类与数据相关的校验在 service 层完成
+func RequirePermission(perms ...app.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !app.PrincipalFromContext(c.Request.Context()).Can(perms...) {
+			app.NewResponse(c).ToErrorResponse(errcode.Forbidden)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
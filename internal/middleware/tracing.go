@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"blog-service/pkg/trace"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	HeaderRequestID   = "X-Request-ID"
+	HeaderTraceparent = "traceparent"
+)
+
+// 接收或生成 X-Request-ID 与 W3C traceparent，写入请求上下文并回写到响应头
+func Tracing() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		t := trace.Trace{
+			RequestID: c.GetHeader(HeaderRequestID),
+			SpanID:    trace.NewSpanID(),
+		}
+		if !validRequestID(t.RequestID) {
+			t.RequestID = trace.NewRequestID()
+		}
+		if traceID, parentID, flags, ok := trace.ParseTraceparent(c.GetHeader(HeaderTraceparent)); ok {
+			t.TraceID, t.ParentSpanID, t.Flags = traceID, parentID, flags
+		} else {
+			t.TraceID = trace.NewTraceID()
+		}
+
+		c.Request = c.Request.WithContext(trace.NewContext(c.Request.Context(), t))
+		c.Set("request_id", t.RequestID)
+		c.Header(HeaderRequestID, t.RequestID)
+		c.Header(HeaderTraceparent, t.Traceparent())
+		c.Next()
+	}
+}
+
+// 只接受长度合理的可打印 ASCII，避免日志注入
+func validRequestID(id string) bool {
+	if id == "" || len(id) > 128 {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,249 @@
+package migration
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 迁移脚本按数据库方言分目录存放，文件名格式为 <版本号>_<名称>.up.sql / .down.sql
+//
+//go:embed sql
+var sqlFS embed.FS
+
+// 记录已执行迁移版本的表
+const tableName = "blog_schema_migrations"
+
+type Migration struct {
+	Version uint64
+	Name    string
+	Up      string
+	Down    string
+}
+
+type Status struct {
+	Migration *Migration
+	Applied   bool
+	AppliedOn uint32
+}
+
+type Migrator struct {
+	db         *gorm.DB
+	migrations []*Migration
+}
+
+// 根据数据库方言加载对应目录下的迁移脚本
+func NewMigrator(db *gorm.DB, dbType string) (*Migrator, error) {
+	dialect, err := dialectDir(dbType)
+	if err != nil {
+		return nil, err
+	}
+	migrations, err := load(path.Join("sql", dialect))
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+func dialectDir(dbType string) (string, error) {
+	switch strings.ToLower(dbType) {
+	case "", "mysql":
+		return "mysql", nil
+	case "postgres", "postgresql":
+		return "postgres", nil
+	case "sqlite", "sqlite3":
+		return "sqlite", nil
+	}
+	return "", fmt.Errorf("unsupported database type: %s", dbType)
+}
+
+func load(dir string) ([]*Migration, error) {
+	entries, err := fs.ReadDir(sqlFS, dir)
+	if err != nil {
+		return nil, err
+	}
+	byVersion := make(map[uint64]*Migration)
+	for _, entry := range entries {
+		fileName := entry.Name()
+		var direction string
+		switch {
+		case strings.HasSuffix(fileName, ".up.sql"):
+			direction = "up"
+		case strings.HasSuffix(fileName, ".down.sql"):
+			direction = "down"
+		default:
+			continue
+		}
+		base := strings.TrimSuffix(fileName, "."+direction+".sql")
+		versionStr, name, ok := strings.Cut(base, "_")
+		if !ok {
+			return nil, fmt.Errorf("migration %s: file name must be <version>_<name>.%s.sql", fileName, direction)
+		}
+		version, err := strconv.ParseUint(versionStr, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("migration %s: invalid version: %v", fileName, err)
+		}
+		content, err := fs.ReadFile(sqlFS, path.Join(dir, fileName))
+		if err != nil {
+			return nil, err
+		}
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: name}
+			byVersion[version] = m
+		} else if m.Name != name {
+			return nil, fmt.Errorf("migration version %d is used by both %s and %s", version, m.Name, name)
+		}
+		if direction == "up" {
+			m.Up = string(content)
+		} else {
+			m.Down = string(content)
+		}
+	}
+
+	migrations := make([]*Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up script", m.Version, m.Name)
+		}
+		migrations = append(migrations, m)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+func (m *Migrator) ensureTable() error {
+	return m.db.Exec("CREATE TABLE IF NOT EXISTS " + tableName + " (" +
+		"version BIGINT NOT NULL PRIMARY KEY, " +
+		"name VARCHAR(255) NOT NULL, " +
+		"applied_on BIGINT NOT NULL)").Error
+}
+
+type appliedRow struct {
+	Version   uint64
+	AppliedOn uint32
+}
+
+func (m *Migrator) applied() (map[uint64]uint32, error) {
+	if err := m.ensureTable(); err != nil {
+		return nil, err
+	}
+	var rows []appliedRow
+	if err := m.db.Table(tableName).Select("version, applied_on").Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	applied := make(map[uint64]uint32, len(rows))
+	for _, row := range rows {
+		applied[row.Version] = row.AppliedOn
+	}
+	return applied, nil
+}
+
+// 返回所有迁移及其执行状态，按版本号升序排列
+func (m *Migrator) Status() ([]*Status, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+	statuses := make([]*Status, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		appliedOn, ok := applied[migration.Version]
+		statuses = append(statuses, &Status{Migration: migration, Applied: ok, AppliedOn: appliedOn})
+	}
+	return statuses, nil
+}
+
+// 按版本顺序执行所有未执行的迁移，返回本次执行的迁移
+func (m *Migrator) Up() ([]*Migration, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+	var done []*Migration
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+		err := m.db.Transaction(func(tx *gorm.DB) error {
+			if err := execScript(tx, migration.Up); err != nil {
+				return err
+			}
+			return tx.Exec("INSERT INTO "+tableName+" (version, name, applied_on) VALUES (?, ?, ?)",
+				migration.Version, migration.Name, time.Now().Unix()).Error
+		})
+		if err != nil {
+			return done, fmt.Errorf("migration %d_%s up: %w", migration.Version, migration.Name, err)
+		}
+		done = append(done, migration)
+	}
+	return done, nil
+}
+
+// 按版本倒序回滚最近执行的 steps 个迁移，返回本次回滚的迁移
+func (m *Migrator) Down(steps int) ([]*Migration, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+	var done []*Migration
+	for i := len(m.migrations) - 1; i >= 0 && len(done) < steps; i-- {
+		migration := m.migrations[i]
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+		if migration.Down == "" {
+			return done, fmt.Errorf("migration %d_%s has no down script", migration.Version, migration.Name)
+		}
+		err := m.db.Transaction(func(tx *gorm.DB) error {
+			if err := execScript(tx, migration.Down); err != nil {
+				return err
+			}
+			return tx.Exec("DELETE FROM "+tableName+" WHERE version = ?", migration.Version).Error
+		})
+		if err != nil {
+			return done, fmt.Errorf("migration %d_%s down: %w", migration.Version, migration.Name, err)
+		}
+		done = append(done, migration)
+	}
+	return done, nil
+}
+
+// 逐条执行脚本中的语句，语句以行尾的分号分隔
+func execScript(tx *gorm.DB, script string) error {
+	for _, stmt := range splitStatements(script) {
+		if err := tx.Exec(stmt).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func splitStatements(script string) []string {
+	var stmts []string
+	var b strings.Builder
+	for _, line := range strings.Split(script, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+		b.WriteString(line)
+		b.WriteString("\n")
+		if strings.HasSuffix(trimmed, ";") {
+			stmts = append(stmts, strings.TrimSpace(b.String()))
+			b.Reset()
+		}
+	}
+	if rest := strings.TrimSpace(b.String()); rest != "" {
+		stmts = append(stmts, rest)
+	}
+	return stmts
+}
@@ -0,0 +1,18 @@
+CREATE TABLE `blog_comment` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `article_id` int(10) unsigned NOT NULL COMMENT '文章 ID',
+  `parent_id` int(10) unsigned NOT NULL DEFAULT '0' COMMENT '回复的评论 ID，0 为直接评论文章',
+  `author` varchar(100) NOT NULL DEFAULT '' COMMENT '评论者昵称',
+  `email` varchar(255) NOT NULL DEFAULT '' COMMENT '评论者邮箱',
+  `content` text COMMENT '评论内容',
+  `state` tinyint(3) unsigned DEFAULT '0' COMMENT '状态 0 为待审核、1 为已通过、2 为垃圾评论、3 为已删除',
+  `created_on` int(10) unsigned DEFAULT '0' COMMENT '创建时间',
+  `created_by` varchar(100) DEFAULT '' COMMENT '创建人',
+  `modified_on` int(10) unsigned DEFAULT '0' COMMENT '修改时间',
+  `modified_by` varchar(100) DEFAULT '' COMMENT '修改人',
+  `deleted_on` int(10) unsigned DEFAULT '0' COMMENT '删除时间',
+  `is_del` tinyint(3) unsigned DEFAULT '0' COMMENT '是否删除 0 为未删除、1 为已删除',
+  PRIMARY KEY (`id`),
+  KEY `idx_article_state` (`article_id`, `state`),
+  KEY `idx_state` (`state`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='文章评论';
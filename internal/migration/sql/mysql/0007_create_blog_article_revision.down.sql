@@ -0,0 +1 @@
+DROP TABLE IF EXISTS `blog_article_revision`;
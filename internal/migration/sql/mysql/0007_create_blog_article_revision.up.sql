@@ -0,0 +1,19 @@
+CREATE TABLE `blog_article_revision` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `article_id` int(10) unsigned NOT NULL COMMENT '文章 ID',
+  `title` varchar(100) DEFAULT '' COMMENT '文章标题',
+  `desc` varchar(255) DEFAULT '' COMMENT '文章简述',
+  `content` longtext COMMENT '文章内容',
+  `created_on` int(10) unsigned DEFAULT '0' COMMENT '创建时间',
+  `created_by` varchar(100) DEFAULT '' COMMENT '修改文章的用户',
+  `modified_on` int(10) unsigned DEFAULT '0' COMMENT '修改时间',
+  `modified_by` varchar(100) DEFAULT '' COMMENT '修改人',
+  `deleted_on` int(10) unsigned DEFAULT '0' COMMENT '删除时间',
+  `is_del` tinyint(3) unsigned DEFAULT '0' COMMENT '是否删除 0 为未删除、1 为已删除',
+  PRIMARY KEY (`id`),
+  KEY `idx_article_id` (`article_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='文章修订历史';
+-- 已有文章以当前内容作为第一个修订
+INSERT INTO `blog_article_revision` (`article_id`, `title`, `desc`, `content`, `created_on`, `created_by`)
+SELECT `id`, `title`, `desc`, `content`, IF(`modified_on` > 0, `modified_on`, `created_on`), IF(`modified_by` <> '', `modified_by`, `created_by`)
+FROM `blog_article` WHERE `is_del` = 0;
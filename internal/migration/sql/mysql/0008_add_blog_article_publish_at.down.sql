@@ -0,0 +1,4 @@
+ALTER TABLE `blog_article`
+  DROP KEY `idx_state_publish_at`,
+  DROP COLUMN `publish_at`,
+  MODIFY COLUMN `state` tinyint(3) unsigned DEFAULT '1' COMMENT '状态 0 为禁用、1 为启用';
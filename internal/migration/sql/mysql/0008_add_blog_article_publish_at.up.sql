@@ -0,0 +1,6 @@
+ALTER TABLE `blog_article`
+  ADD COLUMN `publish_at` int(10) unsigned NOT NULL DEFAULT '0' COMMENT '发布时间，定时发布的文章为计划发布时间' AFTER `state`,
+  MODIFY COLUMN `state` tinyint(3) unsigned DEFAULT '1' COMMENT '状态 0 为草稿、1 为已发布、2 为待审核、3 为定时发布、4 为已归档',
+  ADD KEY `idx_state_publish_at` (`state`, `publish_at`);
+-- 已发布的文章以创建时间作为发布时间
+UPDATE `blog_article` SET `publish_at` = `created_on` WHERE `state` = 1;
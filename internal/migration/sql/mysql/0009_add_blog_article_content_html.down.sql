@@ -0,0 +1,3 @@
+ALTER TABLE `blog_article`
+  DROP COLUMN `content_html`,
+  DROP COLUMN `content_toc`;
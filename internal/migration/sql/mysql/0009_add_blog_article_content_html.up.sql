@@ -0,0 +1,3 @@
+ALTER TABLE `blog_article`
+  ADD COLUMN `content_html` longtext COMMENT '由 Markdown 正文渲染的 HTML' AFTER `content`,
+  ADD COLUMN `content_toc` text COMMENT '由正文标题生成的目录，JSON 格式' AFTER `content_html`;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS `blog_slug_redirect`;
+ALTER TABLE `blog_tag` DROP KEY `uk_slug`, DROP COLUMN `slug`;
+ALTER TABLE `blog_article` DROP KEY `uk_slug`, DROP COLUMN `slug`;
@@ -0,0 +1,21 @@
+ALTER TABLE `blog_article` ADD COLUMN `slug` varchar(100) NOT NULL DEFAULT '' COMMENT '由标题生成的 slug' AFTER `title`;
+ALTER TABLE `blog_tag` ADD COLUMN `slug` varchar(100) NOT NULL DEFAULT '' COMMENT '由名称生成的 slug' AFTER `name`;
+-- 已有数据先以 ID 作为 slug，可通过 generate-slugs 子命令按标题重新生成
+UPDATE `blog_article` SET `slug` = CAST(`id` AS CHAR);
+UPDATE `blog_tag` SET `slug` = CAST(`id` AS CHAR);
+ALTER TABLE `blog_article` ADD UNIQUE KEY `uk_slug` (`slug`);
+ALTER TABLE `blog_tag` ADD UNIQUE KEY `uk_slug` (`slug`);
+CREATE TABLE `blog_slug_redirect` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `kind` varchar(20) NOT NULL COMMENT '类型 article 或 tag',
+  `old_slug` varchar(100) NOT NULL COMMENT '修改前的 slug',
+  `target_id` int(10) unsigned NOT NULL COMMENT '文章或标签 ID',
+  `created_on` int(10) unsigned DEFAULT '0' COMMENT '创建时间',
+  `created_by` varchar(100) DEFAULT '' COMMENT '创建人',
+  `modified_on` int(10) unsigned DEFAULT '0' COMMENT '修改时间',
+  `modified_by` varchar(100) DEFAULT '' COMMENT '修改人',
+  `deleted_on` int(10) unsigned DEFAULT '0' COMMENT '删除时间',
+  `is_del` tinyint(3) unsigned DEFAULT '0' COMMENT '是否删除 0 为未删除、1 为已删除',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_kind_old_slug` (`kind`, `old_slug`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='slug 修改后的旧地址重定向';
@@ -0,0 +1,4 @@
+ALTER TABLE `blog_article_revision`
+  DROP COLUMN `tag_ids`,
+  DROP COLUMN `state`,
+  DROP COLUMN `cover_image_url`;
@@ -0,0 +1,5 @@
+-- 升级前的修订没有记录封面、状态和标签，保持默认值，恢复时不修改这些字段
+ALTER TABLE `blog_article_revision`
+  ADD COLUMN `cover_image_url` varchar(255) DEFAULT '' COMMENT '封面图片地址' AFTER `content`,
+  ADD COLUMN `state` tinyint(3) unsigned DEFAULT NULL COMMENT '文章状态' AFTER `cover_image_url`,
+  ADD COLUMN `tag_ids` varchar(255) DEFAULT '' COMMENT '标签 ID，逗号分隔' AFTER `state`;
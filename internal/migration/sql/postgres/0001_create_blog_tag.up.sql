@@ -0,0 +1,11 @@
+CREATE TABLE blog_tag (
+  id SERIAL PRIMARY KEY,
+  name VARCHAR(100) NOT NULL DEFAULT '',
+  created_on INTEGER NOT NULL DEFAULT 0,
+  created_by VARCHAR(100) NOT NULL DEFAULT '',
+  modified_on INTEGER NOT NULL DEFAULT 0,
+  modified_by VARCHAR(100) NOT NULL DEFAULT '',
+  deleted_on INTEGER NOT NULL DEFAULT 0,
+  is_del SMALLINT NOT NULL DEFAULT 0,
+  state SMALLINT NOT NULL DEFAULT 1
+);
@@ -0,0 +1,14 @@
+CREATE TABLE blog_article (
+  id SERIAL PRIMARY KEY,
+  title VARCHAR(100) NOT NULL DEFAULT '',
+  "desc" VARCHAR(255) NOT NULL DEFAULT '',
+  cover_image_url VARCHAR(255) NOT NULL DEFAULT '',
+  content TEXT NOT NULL DEFAULT '',
+  created_on INTEGER NOT NULL DEFAULT 0,
+  created_by VARCHAR(100) NOT NULL DEFAULT '',
+  modified_on INTEGER NOT NULL DEFAULT 0,
+  modified_by VARCHAR(100) NOT NULL DEFAULT '',
+  deleted_on INTEGER NOT NULL DEFAULT 0,
+  is_del SMALLINT NOT NULL DEFAULT 0,
+  state SMALLINT NOT NULL DEFAULT 1
+);
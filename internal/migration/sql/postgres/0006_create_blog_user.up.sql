@@ -0,0 +1,14 @@
+CREATE TABLE blog_user (
+  id SERIAL PRIMARY KEY,
+  username VARCHAR(100) NOT NULL,
+  password VARCHAR(100) NOT NULL DEFAULT '',
+  role VARCHAR(20) NOT NULL DEFAULT 'author',
+  state SMALLINT NOT NULL DEFAULT 1,
+  created_on INTEGER NOT NULL DEFAULT 0,
+  created_by VARCHAR(100) NOT NULL DEFAULT '',
+  modified_on INTEGER NOT NULL DEFAULT 0,
+  modified_by VARCHAR(100) NOT NULL DEFAULT '',
+  deleted_on INTEGER NOT NULL DEFAULT 0,
+  is_del SMALLINT NOT NULL DEFAULT 0
+);
+CREATE UNIQUE INDEX uk_username ON blog_user (username);
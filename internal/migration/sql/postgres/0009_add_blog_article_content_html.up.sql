@@ -0,0 +1,2 @@
+ALTER TABLE blog_article ADD COLUMN content_html TEXT NOT NULL DEFAULT '';
+ALTER TABLE blog_article ADD COLUMN content_toc TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS blog_slug_redirect;
+DROP INDEX IF EXISTS uk_tag_slug;
+DROP INDEX IF EXISTS uk_article_slug;
+ALTER TABLE blog_tag DROP COLUMN slug;
+ALTER TABLE blog_article DROP COLUMN slug;
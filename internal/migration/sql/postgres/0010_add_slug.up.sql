@@ -0,0 +1,20 @@
+ALTER TABLE blog_article ADD COLUMN slug VARCHAR(100) NOT NULL DEFAULT '';
+ALTER TABLE blog_tag ADD COLUMN slug VARCHAR(100) NOT NULL DEFAULT '';
+-- 已有数据先以 ID 作为 slug，可通过 generate-slugs 子命令按标题重新生成
+UPDATE blog_article SET slug = CAST(id AS VARCHAR);
+UPDATE blog_tag SET slug = CAST(id AS VARCHAR);
+CREATE UNIQUE INDEX uk_article_slug ON blog_article (slug);
+CREATE UNIQUE INDEX uk_tag_slug ON blog_tag (slug);
+CREATE TABLE blog_slug_redirect (
+  id SERIAL PRIMARY KEY,
+  kind VARCHAR(20) NOT NULL,
+  old_slug VARCHAR(100) NOT NULL,
+  target_id INTEGER NOT NULL,
+  created_on INTEGER NOT NULL DEFAULT 0,
+  created_by VARCHAR(100) NOT NULL DEFAULT '',
+  modified_on INTEGER NOT NULL DEFAULT 0,
+  modified_by VARCHAR(100) NOT NULL DEFAULT '',
+  deleted_on INTEGER NOT NULL DEFAULT 0,
+  is_del SMALLINT NOT NULL DEFAULT 0
+);
+CREATE UNIQUE INDEX uk_slug_redirect_kind_old_slug ON blog_slug_redirect (kind, old_slug);
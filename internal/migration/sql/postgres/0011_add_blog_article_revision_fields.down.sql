@@ -0,0 +1,3 @@
+ALTER TABLE blog_article_revision DROP COLUMN tag_ids;
+ALTER TABLE blog_article_revision DROP COLUMN state;
+ALTER TABLE blog_article_revision DROP COLUMN cover_image_url;
@@ -0,0 +1,4 @@
+-- 升级前的修订没有记录封面、状态和标签，保持默认值，恢复时不修改这些字段
+ALTER TABLE blog_article_revision ADD COLUMN cover_image_url VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE blog_article_revision ADD COLUMN state SMALLINT;
+ALTER TABLE blog_article_revision ADD COLUMN tag_ids VARCHAR(255) NOT NULL DEFAULT '';
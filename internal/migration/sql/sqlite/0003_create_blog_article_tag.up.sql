@@ -0,0 +1,13 @@
+CREATE TABLE blog_article_tag (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  article_id INTEGER NOT NULL,
+  tag_id INTEGER NOT NULL DEFAULT 0,
+  created_on INTEGER NOT NULL DEFAULT 0,
+  created_by VARCHAR(100) NOT NULL DEFAULT '',
+  modified_on INTEGER NOT NULL DEFAULT 0,
+  modified_by VARCHAR(100) NOT NULL DEFAULT '',
+  deleted_on INTEGER NOT NULL DEFAULT 0,
+  is_del SMALLINT NOT NULL DEFAULT 0
+);
+CREATE UNIQUE INDEX uk_article_tag ON blog_article_tag (article_id, tag_id);
+CREATE INDEX idx_article_tag_tag_id ON blog_article_tag (tag_id);
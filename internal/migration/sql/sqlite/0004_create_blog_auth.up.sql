@@ -0,0 +1,12 @@
+CREATE TABLE blog_auth (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  app_key VARCHAR(20) NOT NULL DEFAULT '',
+  app_secret VARCHAR(50) NOT NULL DEFAULT '',
+  created_on INTEGER NOT NULL DEFAULT 0,
+  created_by VARCHAR(100) NOT NULL DEFAULT '',
+  modified_on INTEGER NOT NULL DEFAULT 0,
+  modified_by VARCHAR(100) NOT NULL DEFAULT '',
+  deleted_on INTEGER NOT NULL DEFAULT 0,
+  is_del SMALLINT NOT NULL DEFAULT 0
+);
+CREATE UNIQUE INDEX uk_app_key ON blog_auth (app_key);
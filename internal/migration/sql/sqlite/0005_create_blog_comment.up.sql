@@ -0,0 +1,17 @@
+CREATE TABLE blog_comment (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  article_id INTEGER NOT NULL,
+  parent_id INTEGER NOT NULL DEFAULT 0,
+  author VARCHAR(100) NOT NULL DEFAULT '',
+  email VARCHAR(255) NOT NULL DEFAULT '',
+  content TEXT,
+  state SMALLINT NOT NULL DEFAULT 0,
+  created_on INTEGER NOT NULL DEFAULT 0,
+  created_by VARCHAR(100) NOT NULL DEFAULT '',
+  modified_on INTEGER NOT NULL DEFAULT 0,
+  modified_by VARCHAR(100) NOT NULL DEFAULT '',
+  deleted_on INTEGER NOT NULL DEFAULT 0,
+  is_del SMALLINT NOT NULL DEFAULT 0
+);
+CREATE INDEX idx_comment_article_state ON blog_comment (article_id, state);
+CREATE INDEX idx_comment_state ON blog_comment (state);
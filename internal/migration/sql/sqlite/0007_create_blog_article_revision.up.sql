@@ -0,0 +1,20 @@
+CREATE TABLE blog_article_revision (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  article_id INTEGER NOT NULL,
+  title VARCHAR(100) NOT NULL DEFAULT '',
+  "desc" VARCHAR(255) NOT NULL DEFAULT '',
+  content TEXT NOT NULL DEFAULT '',
+  created_on INTEGER NOT NULL DEFAULT 0,
+  created_by VARCHAR(100) NOT NULL DEFAULT '',
+  modified_on INTEGER NOT NULL DEFAULT 0,
+  modified_by VARCHAR(100) NOT NULL DEFAULT '',
+  deleted_on INTEGER NOT NULL DEFAULT 0,
+  is_del SMALLINT NOT NULL DEFAULT 0
+);
+CREATE INDEX idx_article_revision_article_id ON blog_article_revision (article_id);
+-- 已有文章以当前内容作为第一个修订
+INSERT INTO blog_article_revision (article_id, title, "desc", content, created_on, created_by)
+SELECT id, title, "desc", content,
+  CASE WHEN modified_on > 0 THEN modified_on ELSE created_on END,
+  CASE WHEN modified_by <> '' THEN modified_by ELSE created_by END
+FROM blog_article WHERE is_del = 0;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_article_state_publish_at;
+ALTER TABLE blog_article DROP COLUMN publish_at;
@@ -0,0 +1,4 @@
+ALTER TABLE blog_article ADD COLUMN publish_at INTEGER NOT NULL DEFAULT 0;
+CREATE INDEX idx_article_state_publish_at ON blog_article (state, publish_at);
+-- 已发布的文章以创建时间作为发布时间
+UPDATE blog_article SET publish_at = created_on WHERE state = 1;
@@ -0,0 +1,2 @@
+ALTER TABLE blog_article DROP COLUMN content_toc;
+ALTER TABLE blog_article DROP COLUMN content_html;
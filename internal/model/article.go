@@ -1,26 +1,248 @@
 package model
 
-import "blog-service/pkg/app"
+import (
+	"fmt"
+
+	"blog-service/pkg/app"
+	"gorm.io/gorm"
+)
+
+// 文章的发布状态，取值兼容原有的 0 未发布、1 已发布，只有已发布的文章对读者可见
+const (
+	ARTICLE_STATE_DRAFT     = 0
+	ARTICLE_STATE_PUBLISHED = 1
+	ARTICLE_STATE_IN_REVIEW = 2
+	ARTICLE_STATE_SCHEDULED = 3
+	ARTICLE_STATE_ARCHIVED  = 4
+)
+
+var articleStateNames = map[uint8]string{
+	ARTICLE_STATE_DRAFT:     "draft",
+	ARTICLE_STATE_PUBLISHED: "published",
+	ARTICLE_STATE_IN_REVIEW: "in_review",
+	ARTICLE_STATE_SCHEDULED: "scheduled",
+	ARTICLE_STATE_ARCHIVED:  "archived",
+}
+
+func ArticleStateName(state uint8) string {
+	if name, ok := articleStateNames[state]; ok {
+		return name
+	}
+	return fmt.Sprintf("unknown(%d)", state)
+}
 
 type Article struct {
 	*Model
-	Name  string `json:"name"`
-	State uint8  `json:"state"`
+	Title   string `json:"title"`
+	Slug    string `json:"slug"`
+	Desc    string `json:"desc"`
+	Content string `json:"content"`
+	// 由 Markdown 正文渲染的 HTML 和 JSON 格式的目录，随正文一起写入
+	ContentHTML   string `json:"content_html"`
+	ContentTOC    string `json:"content_toc"`
+	CoverImageUrl string `json:"cover_image_url"`
+	State         uint8  `json:"state"`
+	// 已发布文章的发布时间，定时发布的文章为计划发布时间
+	PublishAt uint32 `json:"publish_at"`
 }
 
-// 定义一个结构体，用于描述 Swagger 文档中的标签列表和分页信息
+// 定义一个结构体，用于描述 Swagger 文档中的文章列表和分页信息
 type ArticleSwagger struct {
 	List  []*Article
 	Pager *app.Pager
 }
 
-type ArticleTag struct {
-	*Model
-	TagID     uint32 `json:"tag_id"`
-	ArticleID uint32 `json:"article_id"`
+func (a Article) TableName() string {
+	return "blog_article"
+}
+
+func (a Article) Create(db *gorm.DB) (*Article, error) {
+	if err := db.Create(&a).Error; err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// 只更新 values 中给出的字段，避免零值覆盖
+func (a Article) Update(db *gorm.DB, values interface{}) error {
+	return db.Model(&Article{}).Where("id = ? AND is_del = ?", a.ID, 0).Updates(values).Error
+}
+
+func (a Article) Get(db *gorm.DB) (Article, error) {
+	var article Article
+	db = db.Where("id = ? AND state = ? AND is_del = ?", a.ID, a.State, 0)
+	err := db.First(&article).Error
+	if err != nil {
+		return article, err
+	}
+	return article, nil
+}
+
+func (a Article) GetBySlug(db *gorm.DB) (Article, error) {
+	var article Article
+	err := db.Where("slug = ? AND state = ? AND is_del = ?", a.Slug, a.State, 0).First(&article).Error
+	if err != nil {
+		return article, err
+	}
+	return article, nil
+}
+
+// 不区分状态查询文章，用于写操作前的校验
+func (a Article) GetByID(db *gorm.DB) (Article, error) {
+	var article Article
+	err := db.Where("id = ? AND is_del = ?", a.ID, 0).First(&article).Error
+	if err != nil {
+		return article, err
+	}
+	return article, nil
+}
+
+// 按 ID 批量查询文章，不保证返回顺序
+func (a Article) ListByIDs(db *gorm.DB, ids []uint32) ([]*Article, error) {
+	var articles []*Article
+	if len(ids) == 0 {
+		return articles, nil
+	}
+	err := db.Where("id IN ? AND state = ? AND is_del = ?", ids, a.State, 0).Find(&articles).Error
+	if err != nil {
+		return nil, err
+	}
+	return articles, nil
+}
+
+// 仅当文章仍处于 from 状态时修改状态，返回是否修改成功，避免覆盖并发的状态变更
+func (a Article) Transition(db *gorm.DB, from, to uint8, values map[string]interface{}) (bool, error) {
+	values["state"] = to
+	res := db.Model(&Article{}).Where("id = ? AND state = ? AND is_del = ?", a.ID, from, 0).Updates(values)
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected > 0, nil
+}
+
+// 按 ID 正序查询 ID 大于 afterID 的文章，不区分状态，用于批量处理全部文章
+func (a Article) ListAfterID(db *gorm.DB, afterID uint32, limit int) ([]*Article, error) {
+	var articles []*Article
+	err := db.Where("id > ? AND is_del = ?", afterID, 0).Order("id").Limit(limit).Find(&articles).Error
+	if err != nil {
+		return nil, err
+	}
+	return articles, nil
+}
+
+// 查询计划发布时间不晚于 now 的定时发布文章
+func (a Article) ListDue(db *gorm.DB, now uint32, limit int) ([]*Article, error) {
+	var articles []*Article
+	err := db.Where("state = ? AND publish_at <= ? AND is_del = ?", ARTICLE_STATE_SCHEDULED, now, 0).
+		Order("publish_at").Limit(limit).Find(&articles).Error
+	if err != nil {
+		return nil, err
+	}
+	return articles, nil
+}
+
+func (a Article) Delete(db *gorm.DB) error {
+	return db.Where("id = ? AND is_del = ?", a.Model.ID, 0).Delete(&a).Error
+}
+
+// 发布时间，升级前发布的文章没有 publish_at，以创建时间代替
+func (a *Article) PublishedOn() uint32 {
+	if a.PublishAt > 0 {
+		return a.PublishAt
+	}
+	return a.CreatedOn
+}
+
+// 按发布时间倒序查询最新的 limit 篇文章，排序规则与 PublishedOn 一致
+func (a Article) ListLatest(db *gorm.DB, tagID uint32, limit int) ([]*Article, error) {
+	var articles []*Article
+	db = a.scopeTagID(db, tagID)
+	err := db.Select("ar.*").Where("ar.state = ? AND ar.is_del = ?", a.State, 0).
+		Order("CASE WHEN ar.publish_at > 0 THEN ar.publish_at ELSE ar.created_on END DESC, ar.id DESC").
+		Limit(limit).Find(&articles).Error
+	if err != nil {
+		return nil, err
+	}
+	return articles, nil
+}
+
+// 查询文章列表，tagID 大于 0 时通过 blog_article_tag 关联表按标签过滤
+func (a Article) List(db *gorm.DB, tagID uint32, pageOffset, pageSize int) ([]*Article, error) {
+	var articles []*Article
+	if pageOffset >= 0 && pageSize > 0 {
+		db = db.Offset(pageOffset).Limit(pageSize)
+	}
+	db = a.scopeCreatedBy(a.scopeTagID(db, tagID))
+	err := db.Select("ar.*").Where("ar.state = ? AND ar.is_del = ?", a.State, 0).Order("ar.id DESC").Find(&articles).Error
+	if err != nil {
+		return nil, err
+	}
+	return articles, nil
+}
+
+// 按游标查询文章列表，结果按 (created_on, id) 倒序排列
+func (a Article) ListByCursor(db *gorm.DB, tagID uint32, cursor *app.Cursor, limit int) ([]*Article, error) {
+	var articles []*Article
+	db = scopeCursor(a.scopeCreatedBy(a.scopeTagID(db, tagID)), "ar.", cursor, limit)
+	err := db.Select("ar.*").Where("ar.state = ? AND ar.is_del = ?", a.State, 0).Find(&articles).Error
+	if err != nil {
+		return nil, err
+	}
+	if cursor != nil && cursor.Prev {
+		reverse(articles)
+	}
+	return articles, nil
+}
+
+func (a Article) Count(db *gorm.DB, tagID uint32) (int64, error) {
+	var count int64
+	db = a.scopeCreatedBy(a.scopeTagID(db, tagID))
+	err := db.Where("ar.state = ? AND ar.is_del = ?", a.State, 0).Count(&count).Error
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// 文章最近修改时间和数量，用于生成订阅源等内容的缓存校验值
+type ArticleStat struct {
+	LastModified uint32
+	Total        int64
+}
+
+func (a Article) Stat(db *gorm.DB, tagID uint32) (ArticleStat, error) {
+	var stat ArticleStat
+	db = a.scopeTagID(db, tagID)
+	err := db.Select("COALESCE(MAX(ar.modified_on), 0) AS last_modified, COUNT(*) AS total").
+		Where("ar.state = ? AND ar.is_del = ?", a.State, 0).Scan(&stat).Error
+	if err != nil {
+		return stat, err
+	}
+	return stat, nil
+}
+
+func (a Article) ListModified(db *gorm.DB) ([]*ModifiedRow, error) {
+	var rows []*ModifiedRow
+	err := db.Model(&Article{}).Select("id, slug, modified_on").
+		Where("state = ? AND is_del = ?", a.State, 0).Order("id").Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+func (a Article) scopeTagID(db *gorm.DB, tagID uint32) *gorm.DB {
+	db = db.Table("blog_article AS ar")
+	if tagID > 0 {
+		db = db.Joins("INNER JOIN blog_article_tag AS at ON at.article_id = ar.id AND at.tag_id = ? AND at.is_del = ?", tagID, 0)
+	}
+	return db
 }
 
-// TableName 方法应该属于 ArticleTag 类型，并且接收者应该是 ArticleTag 类型的值
-func (a ArticleTag) TableName() string {
-	return "blog_article_tag"
+// CreatedBy 不为空时只查询该用户创建的文章
+func (a Article) scopeCreatedBy(db *gorm.DB) *gorm.DB {
+	if a.Model != nil && a.CreatedBy != "" {
+		db = db.Where("ar.created_by = ?", a.CreatedBy)
+	}
+	return db
 }
@@ -0,0 +1,100 @@
+package model
+
+import (
+	"strconv"
+	"strings"
+
+	"blog-service/pkg/app"
+	"gorm.io/gorm"
+)
+
+// 文章每次创建或更新后保存的快照，写入后不再修改，CreatedBy 为修改文章的用户
+// 升级前的修订没有记录封面、状态和标签，State 为 nil，TagIDs 为空
+type ArticleRevision struct {
+	*Model
+	ArticleID     uint32 `json:"article_id"`
+	Title         string `json:"title"`
+	Desc          string `json:"desc"`
+	Content       string `json:"content,omitempty"`
+	CoverImageUrl string `json:"cover_image_url"`
+	State         *uint8 `json:"state"`
+	// 逗号分隔的标签 ID，按 ID 升序
+	TagIDs string `json:"tag_ids"`
+}
+
+func JoinTagIDs(ids []uint32) string {
+	s := make([]string, 0, len(ids))
+	for _, id := range ids {
+		s = append(s, strconv.FormatUint(uint64(id), 10))
+	}
+	return strings.Join(s, ",")
+}
+
+// 忽略无法解析的 ID
+func SplitTagIDs(s string) []uint32 {
+	var ids []uint32
+	for _, part := range strings.Split(s, ",") {
+		if id, err := strconv.ParseUint(part, 10, 32); err == nil {
+			ids = append(ids, uint32(id))
+		}
+	}
+	return ids
+}
+
+// 定义一个结构体，用于描述 Swagger 文档中的修订列表和分页信息
+type ArticleRevisionSwagger struct {
+	List  []*ArticleRevision
+	Pager *app.Pager
+}
+
+func (r ArticleRevision) TableName() string {
+	return "blog_article_revision"
+}
+
+func (r ArticleRevision) Create(db *gorm.DB) (*ArticleRevision, error) {
+	if err := db.Create(&r).Error; err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+func (r ArticleRevision) Get(db *gorm.DB) (ArticleRevision, error) {
+	var revision ArticleRevision
+	err := db.Where("id = ? AND article_id = ? AND is_del = ?", r.ID, r.ArticleID, 0).First(&revision).Error
+	if err != nil {
+		return revision, err
+	}
+	return revision, nil
+}
+
+// 修订列表不返回正文，按 ID 倒序排列，最新的修订在前
+func (r ArticleRevision) List(db *gorm.DB, pageOffset, pageSize int) ([]*ArticleRevision, error) {
+	var revisions []*ArticleRevision
+	if pageOffset >= 0 && pageSize > 0 {
+		db = db.Offset(pageOffset).Limit(pageSize)
+	}
+	err := db.Omit("content").Where("article_id = ? AND is_del = ?", r.ArticleID, 0).Order("id DESC").Find(&revisions).Error
+	if err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
+
+func (r ArticleRevision) Count(db *gorm.DB) (int64, error) {
+	var count int64
+	err := db.Model(&ArticleRevision{}).Where("article_id = ? AND is_del = ?", r.ArticleID, 0).Count(&count).Error
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// 获取 ID 小于 r.ID 的上一个修订
+func (r ArticleRevision) Previous(db *gorm.DB) (ArticleRevision, error) {
+	var revision ArticleRevision
+	err := db.Where("article_id = ? AND id < ? AND is_del = ?", r.ArticleID, r.ID, 0).Order("id DESC").First(&revision).Error
+	if err != nil {
+		return revision, err
+	}
+	return revision, nil
+}
@@ -0,0 +1,78 @@
+package model
+
+import "gorm.io/gorm"
+
+type ArticleTag struct {
+	*Model
+	TagID     uint32 `json:"tag_id"`
+	ArticleID uint32 `json:"article_id"`
+}
+
+// 联表查询文章所属标签时使用的行结构
+type ArticleTagRow struct {
+	ArticleID     uint32
+	TagID         uint32
+	TagName       string
+	TagSlug       string
+	TagState      uint8
+	TagCreatedBy  string
+	TagModifiedBy string
+	TagCreatedOn  uint32
+	TagModifiedOn uint32
+}
+
+// TableName 方法应该属于 ArticleTag 类型，并且接收者应该是 ArticleTag 类型的值
+func (a ArticleTag) TableName() string {
+	return "blog_article_tag"
+}
+
+// 批量查询多篇文章关联的标签
+func (a ArticleTag) ListTagsByAIDs(db *gorm.DB, articleIDs []uint32) ([]*ArticleTagRow, error) {
+	var rows []*ArticleTagRow
+	if len(articleIDs) == 0 {
+		return rows, nil
+	}
+	fields := []string{
+		"at.article_id", "t.id AS tag_id", "t.name AS tag_name", "t.slug AS tag_slug", "t.state AS tag_state",
+		"t.created_by AS tag_created_by", "t.modified_by AS tag_modified_by",
+		"t.created_on AS tag_created_on", "t.modified_on AS tag_modified_on",
+	}
+	err := db.Table("blog_article_tag AS at").Select(fields).
+		Joins("INNER JOIN blog_tag AS t ON at.tag_id = t.id AND t.is_del = ?", 0).
+		Where("at.article_id IN ? AND at.is_del = ?", articleIDs, 0).
+		Order("at.article_id, t.id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+func (a ArticleTag) Create(db *gorm.DB) error {
+	return db.Create(&a).Error
+}
+
+func (a ArticleTag) CreateBatch(db *gorm.DB, articleTags []*ArticleTag) error {
+	if len(articleTags) == 0 {
+		return nil
+	}
+	return db.Create(&articleTags).Error
+}
+
+func (a ArticleTag) DeleteByAID(db *gorm.DB) error {
+	return db.Where("article_id = ? AND is_del = ?", a.ArticleID, 0).Delete(&ArticleTag{}).Error
+}
+
+// 查询关联了标签的文章 ID
+func (a ArticleTag) ListAIDsByTID(db *gorm.DB) ([]uint32, error) {
+	var articleIDs []uint32
+	err := db.Model(&ArticleTag{}).Where("tag_id = ? AND is_del = ?", a.TagID, 0).Pluck("article_id", &articleIDs).Error
+	if err != nil {
+		return nil, err
+	}
+	return articleIDs, nil
+}
+
+func (a ArticleTag) DeleteByTID(db *gorm.DB) error {
+	return db.Where("tag_id = ? AND is_del = ?", a.TagID, 0).Delete(&ArticleTag{}).Error
+}
@@ -0,0 +1,30 @@
+package model
+
+import "gorm.io/gorm"
+
+type Auth struct {
+	*Model
+	AppKey    string `json:"app_key"`
+	AppSecret string `json:"app_secret"`
+}
+
+func (a Auth) TableName() string {
+	return "blog_auth"
+}
+
+func (a Auth) Get(db *gorm.DB) (Auth, error) {
+	var auth Auth
+	db = db.Where("app_key = ? AND app_secret = ? AND is_del = ?", a.AppKey, a.AppSecret, 0)
+	err := db.First(&auth).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return auth, err
+	}
+	return auth, nil
+}
+
+// 只按 AppKey 查询，用于确认已签发的 Token 对应的 AppKey 没有被删除
+func (a Auth) Exists(db *gorm.DB) (bool, error) {
+	var count int64
+	err := db.Model(&Auth{}).Where("app_key = ? AND is_del = ?", a.AppKey, 0).Count(&count).Error
+	return count > 0, err
+}
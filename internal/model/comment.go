@@ -0,0 +1,89 @@
+package model
+
+import (
+	"blog-service/pkg/app"
+	"gorm.io/gorm"
+)
+
+// 评论的审核状态，读者提交的评论默认为待审核，只有已通过的评论对读者可见
+const (
+	COMMENT_STATE_PENDING  = 0
+	COMMENT_STATE_APPROVED = 1
+	COMMENT_STATE_SPAM     = 2
+	COMMENT_STATE_DELETED  = 3
+)
+
+type Comment struct {
+	*Model
+	ArticleID uint32 `json:"article_id"`
+	ParentID  uint32 `json:"parent_id"`
+	Author    string `json:"author"`
+	Email     string `json:"-"`
+	Content   string `json:"content"`
+	State     uint8  `json:"state"`
+}
+
+// 定义一个结构体，用于描述 Swagger 文档中的评论列表和分页信息
+type CommentSwagger struct {
+	List  []*Comment
+	Pager *app.Pager
+}
+
+func (c Comment) TableName() string {
+	return "blog_comment"
+}
+
+func (c Comment) Create(db *gorm.DB) (*Comment, error) {
+	if err := db.Create(&c).Error; err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (c Comment) Get(db *gorm.DB) (Comment, error) {
+	var comment Comment
+	err := db.Where("id = ? AND is_del = ?", c.ID, 0).First(&comment).Error
+	if err != nil {
+		return comment, err
+	}
+	return comment, nil
+}
+
+// 更新审核状态
+func (c Comment) UpdateState(db *gorm.DB, values interface{}) error {
+	return db.Model(&Comment{}).Where("id = ? AND is_del = ?", c.ID, 0).Updates(values).Error
+}
+
+// 按文章和审核状态查询评论，articleID 为 0 时不限文章，按 ID 正序排列，回复通过 parent_id 关联
+func (c Comment) List(db *gorm.DB, pageOffset, pageSize int) ([]*Comment, error) {
+	var comments []*Comment
+	if pageOffset >= 0 && pageSize > 0 {
+		db = db.Offset(pageOffset).Limit(pageSize)
+	}
+	err := c.scope(db).Order("id").Find(&comments).Error
+	if err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
+func (c Comment) Count(db *gorm.DB) (int64, error) {
+	var count int64
+	err := c.scope(db.Model(&Comment{})).Count(&count).Error
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// 删除文章时一并删除其评论
+func (c Comment) DeleteByAID(db *gorm.DB) error {
+	return db.Where("article_id = ? AND is_del = ?", c.ArticleID, 0).Delete(&Comment{}).Error
+}
+
+func (c Comment) scope(db *gorm.DB) *gorm.DB {
+	if c.ArticleID > 0 {
+		db = db.Where("article_id = ?", c.ArticleID)
+	}
+	return db.Where("state = ? AND is_del = ?", c.State, 0)
+}
@@ -2,10 +2,23 @@ package model
 
 import (
 	"blog-service/global"
+	"blog-service/pkg/app"
 	"blog-service/pkg/setting"
 	"fmt"
+	"net"
+	"strings"
+
+	"github.com/glebarez/sqlite"
 	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+	"gorm.io/gorm/schema"
+)
+
+const (
+	STATE_OPEN  = 1
+	STATE_CLOSE = 0
 )
 
 type Model struct {
@@ -18,35 +31,23 @@ type Model struct {
 	IsDel      uint8  `json:"is_del"`
 }
 
-// 数据库连接字符串 DatabaseSettingS
+// 根据 DatabaseSettingS 创建数据库连接，DBType 决定使用的数据库方言
 func NewDBEngine(databaseSetting *setting.DatabaseSettingS) (*gorm.DB, error) {
-	dialector := mysql.Open(fmt.Sprintf("%s:%s@tcp(%s)/%s?charset=%s&parseTime=%t&loc=Local",
-		databaseSetting.UserName,
-		databaseSetting.Password,
-		databaseSetting.Host,
-		databaseSetting.DBName,
-		databaseSetting.Charset,
-		databaseSetting.ParseTime,
-	))
-	// debug
-	fmt.Println(
-		databaseSetting.UserName,
-		databaseSetting.Password,
-		databaseSetting.Host,
-		databaseSetting.DBName,
-		databaseSetting.Charset,
-		databaseSetting.ParseTime,
-	)
-	db, err := gorm.Open(dialector, &gorm.Config{})
+	dialector, err := newDialector(databaseSetting)
+	if err != nil {
+		return nil, err
+	}
+	// 新版本gorm通过 NamingStrategy 禁用表名复数
+	db, err := gorm.Open(dialector, &gorm.Config{
+		NamingStrategy: schema.NamingStrategy{SingularTable: true},
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	if global.ServerSetting.RunMode == "debug" {
-		//db.LogMode(true)
-		db.Debug()
+	if global.ServerSetting.Load().RunMode == "debug" {
+		db.Logger = db.Logger.LogMode(gormlogger.Info)
 	}
-	db.SingularTable(true)
 	//获取通用数据库对象 sql.DB ，然后使用其提供的功能
 	sqlDB, err := db.DB()
 	if err != nil {
@@ -57,9 +58,50 @@ func NewDBEngine(databaseSetting *setting.DatabaseSettingS) (*gorm.DB, error) {
 	deleteCallback(db)
 	sqlDB.SetMaxIdleConns(databaseSetting.MaxIdleConns)
 	sqlDB.SetMaxOpenConns(databaseSetting.MaxOpenConns)
+	if isSQLiteMemory(databaseSetting) {
+		// 内存数据库每个连接都是独立的库，连接关闭后数据随之丢失，只能保持单个常驻连接
+		sqlDB.SetMaxOpenConns(1)
+		sqlDB.SetMaxIdleConns(1)
+		sqlDB.SetConnMaxLifetime(0)
+	}
 	return db, nil
 }
 
+// 支持 mysql、postgres 和 sqlite，DBType 为空时默认使用 mysql
+func newDialector(databaseSetting *setting.DatabaseSettingS) (gorm.Dialector, error) {
+	switch databaseSetting.Dialect() {
+	case setting.DialectMySQL:
+		return mysql.Open(fmt.Sprintf("%s:%s@tcp(%s)/%s?charset=%s&parseTime=%t&loc=Local",
+			databaseSetting.UserName,
+			databaseSetting.Password,
+			databaseSetting.Host,
+			databaseSetting.DBName,
+			databaseSetting.Charset,
+			databaseSetting.ParseTime,
+		)), nil
+	case setting.DialectPostgres:
+		host, port, err := net.SplitHostPort(databaseSetting.Host)
+		if err != nil {
+			host, port = databaseSetting.Host, "5432"
+		}
+		return postgres.Open(fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable TimeZone=Local",
+			host,
+			port,
+			databaseSetting.UserName,
+			databaseSetting.Password,
+			databaseSetting.DBName,
+		)), nil
+	case setting.DialectSQLite:
+		// DBName 为数据库文件路径，":memory:" 表示内存数据库
+		return sqlite.Open(databaseSetting.DBName), nil
+	}
+	return nil, fmt.Errorf("unsupported database type: %s", databaseSetting.DBType)
+}
+
+func isSQLiteMemory(databaseSetting *setting.DatabaseSettingS) bool {
+	return databaseSetting.Dialect() == setting.DialectSQLite && strings.HasPrefix(databaseSetting.DBName, ":memory:")
+}
+
 // 新增行为的回调 基于旧版本gorm编写
 //
 //	func updateTimeStampForCreateCallback(scope *gorm.Scope) {
@@ -80,20 +122,21 @@ func NewDBEngine(databaseSetting *setting.DatabaseSettingS) (*gorm.DB, error) {
 //
 // 基于新版本gorm编写
 func updateTimeStampForCreateCallback(db *gorm.DB) {
-	db.Callback().Create().Before("gorm:cteate").Register("update_timestamp", func(tx *gorm.DB) {
+	db.Callback().Create().Before("gorm:create").Register("update_timestamp", func(tx *gorm.DB) {
 		nowTime := tx.Statement.Context.Value("nowTime")
 		if nowTime == nil {
 			nowTime = tx.NowFunc().Unix()
 		}
-		//tx.Statement.SetColumn("CreatedOn", nowTime)
-		if tx.Statement.Schema != nil {
-			if createdOnField, ok := tx.Statement.Schema.FieldsByName["CreatedOn"]; ok {
-				if createdOnField.Tag.Get("UPDATE") == "false" {
-					tx.Statement.SetColumn("CreatedOn", nowTime)
-				}
-			}
+		if tx.Statement.Schema == nil {
+			return
+		}
+		// 游标分页依赖 created_on，未指定时使用当前时间
+		if _, ok := tx.Statement.Schema.FieldsByName["CreatedOn"]; ok {
+			tx.Statement.SetColumn("CreatedOn", nowTime, true)
+		}
+		if _, ok := tx.Statement.Schema.FieldsByName["ModifiedOn"]; ok {
+			tx.Statement.SetColumn("ModifiedOn", nowTime, true)
 		}
-		tx.Statement.SetColumn("ModifiedOn", nowTime)
 	})
 }
 
@@ -118,3 +161,36 @@ func deleteCallback(db *gorm.DB) {
 		tx.Statement.SetColumn("DeletedOn", nowTime)
 	})
 }
+
+// 只需要 ID、slug 和修改时间的场景使用，如站点地图
+type ModifiedRow struct {
+	ID         uint32
+	Slug       string
+	ModifiedOn uint32
+}
+
+// 游标分页使用的排序键
+func (m *Model) CursorKey() (createdOn, id uint32) {
+	return m.CreatedOn, m.ID
+}
+
+// 按 (created_on, id) 倒序做游标分页，多取一条用于判断是否还有更多数据
+// 向前翻页时按正序查询，调用方需要用 reverse 恢复倒序
+func scopeCursor(db *gorm.DB, prefix string, cursor *app.Cursor, limit int) *gorm.DB {
+	order := prefix + "created_on DESC, " + prefix + "id DESC"
+	if cursor != nil {
+		op := "<"
+		if cursor.Prev {
+			op, order = ">", prefix+"created_on ASC, "+prefix+"id ASC"
+		}
+		db = db.Where("("+prefix+"created_on "+op+" ? OR ("+prefix+"created_on = ? AND "+prefix+"id "+op+" ?))",
+			cursor.CreatedOn, cursor.CreatedOn, cursor.ID)
+	}
+	return db.Order(order).Limit(limit + 1)
+}
+
+func reverse[T any](rows []T) {
+	for i, j := 0, len(rows)-1; i < j; i, j = i+1, j-1 {
+		rows[i], rows[j] = rows[j], rows[i]
+	}
+}
@@ -0,0 +1,59 @@
+package model
+
+import (
+	"gorm.io/gorm"
+)
+
+// 可以通过 slug 访问的对象类型
+const (
+	SLUG_KIND_ARTICLE = "article"
+	SLUG_KIND_TAG     = "tag"
+)
+
+var slugTables = map[string]string{
+	SLUG_KIND_ARTICLE: "blog_article",
+	SLUG_KIND_TAG:     "blog_tag",
+}
+
+// 标题修改导致 slug 变化时记录旧的 slug，通过旧地址访问时重定向到新地址
+type SlugRedirect struct {
+	*Model
+	Kind     string `json:"kind"`
+	OldSlug  string `json:"old_slug"`
+	TargetID uint32 `json:"target_id"`
+}
+
+func (r SlugRedirect) TableName() string {
+	return "blog_slug_redirect"
+}
+
+func (r SlugRedirect) Create(db *gorm.DB) error {
+	return db.Create(&r).Error
+}
+
+func (r SlugRedirect) Get(db *gorm.DB) (SlugRedirect, error) {
+	var redirect SlugRedirect
+	err := db.Where("kind = ? AND old_slug = ? AND is_del = ?", r.Kind, r.OldSlug, 0).First(&redirect).Error
+	if err != nil {
+		return redirect, err
+	}
+	return redirect, nil
+}
+
+// 对象重新使用自己曾经的 slug 时删除对应的重定向，直接物理删除以便唯一索引允许再次记录
+func (r SlugRedirect) DeleteByTarget(db *gorm.DB) error {
+	return db.Exec("DELETE FROM blog_slug_redirect WHERE kind = ? AND old_slug = ? AND target_id = ?",
+		r.Kind, r.OldSlug, r.TargetID).Error
+}
+
+// slug 已被其他对象使用或作为其他对象的旧地址时返回 true，已删除的对象同样占用 slug
+func SlugTaken(db *gorm.DB, kind, slug string, excludeID uint32) (bool, error) {
+	var count int64
+	err := db.Table(slugTables[kind]).Where("slug = ? AND id <> ?", slug, excludeID).Count(&count).Error
+	if err != nil || count > 0 {
+		return count > 0, err
+	}
+	err = db.Model(&SlugRedirect{}).Where("kind = ? AND old_slug = ? AND target_id <> ?", kind, slug, excludeID).
+		Count(&count).Error
+	return count > 0, err
+}
@@ -8,6 +8,7 @@ import (
 type Tag struct {
 	*Model
 	Name  string `json:"name"`
+	Slug  string `json:"slug"`
 	State uint8  `json:"state"`
 }
 
@@ -36,6 +37,51 @@ func (t *Tag) Count(db *gorm.DB) (int64, error) {
 	return count, nil
 }
 
+func (t Tag) Get(db *gorm.DB) (Tag, error) {
+	var tag Tag
+	err := db.Where("id = ? AND is_del = ? AND state = ?", t.ID, 0, t.State).First(&tag).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return tag, err
+	}
+	return tag, nil
+}
+
+// 不区分状态查询标签，用于写操作前的校验
+func (t Tag) GetByID(db *gorm.DB) (Tag, error) {
+	var tag Tag
+	err := db.Where("id = ? AND is_del = ?", t.ID, 0).First(&tag).Error
+	if err != nil {
+		return tag, err
+	}
+	return tag, nil
+}
+
+func (t Tag) GetBySlug(db *gorm.DB) (Tag, error) {
+	var tag Tag
+	err := db.Where("slug = ? AND state = ? AND is_del = ?", t.Slug, t.State, 0).First(&tag).Error
+	if err != nil {
+		return tag, err
+	}
+	return tag, nil
+}
+
+// 按 ID 正序查询 ID 大于 afterID 的标签，不区分状态，用于批量处理全部标签
+func (t Tag) ListAfterID(db *gorm.DB, afterID uint32, limit int) ([]*Tag, error) {
+	var tags []*Tag
+	err := db.Where("id > ? AND is_del = ?", afterID, 0).Order("id").Limit(limit).Find(&tags).Error
+	if err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// 统计 ids 中存在且为指定状态的标签数量
+func (t Tag) CountByIDs(db *gorm.DB, ids []uint32) (int64, error) {
+	var count int64
+	err := db.Model(&Tag{}).Where("id IN ? AND state = ? AND is_del = ?", ids, t.State, 0).Count(&count).Error
+	return count, err
+}
+
 func (t Tag) Create(db *gorm.DB) error {
 	return db.Create(&t).Error
 }
@@ -51,12 +97,42 @@ func (t Tag) Delete(db *gorm.DB) error {
 // 补充定义标签的方法
 func (t Tag) List(db *gorm.DB, pageOffset, pageSize int) ([]*Tag, error) {
 	var tags []*Tag
-	var err error
 	if pageOffset >= 0 && pageSize > 0 {
 		db = db.Offset(pageOffset).Limit(pageSize)
 	}
 	if t.Name != "" {
-		db = db.Where("name =?", t.Name)
+		db = db.Where("name = ?", t.Name)
+	}
+	err := db.Where("state = ? AND is_del = ?", t.State, 0).Find(&tags).Error
+	if err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// 按游标查询标签列表，结果按 (created_on, id) 倒序排列
+func (t Tag) ListByCursor(db *gorm.DB, cursor *app.Cursor, limit int) ([]*Tag, error) {
+	var tags []*Tag
+	if t.Name != "" {
+		db = db.Where("name = ?", t.Name)
+	}
+	db = scopeCursor(db, "", cursor, limit)
+	err := db.Where("state = ? AND is_del = ?", t.State, 0).Find(&tags).Error
+	if err != nil {
+		return nil, err
+	}
+	if cursor != nil && cursor.Prev {
+		reverse(tags)
+	}
+	return tags, nil
+}
+
+func (t Tag) ListModified(db *gorm.DB) ([]*ModifiedRow, error) {
+	var rows []*ModifiedRow
+	err := db.Model(&Tag{}).Select("id, slug, modified_on").
+		Where("state = ? AND is_del = ?", t.State, 0).Order("id").Scan(&rows).Error
+	if err != nil {
+		return nil, err
 	}
-	return tags, err
+	return rows, nil
 }
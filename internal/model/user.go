@@ -0,0 +1,82 @@
+package model
+
+import (
+	"blog-service/pkg/app"
+	"gorm.io/gorm"
+)
+
+type User struct {
+	*Model
+	Username string `json:"username"`
+	Password string `json:"-"`
+	Role     string `json:"role"`
+	State    uint8  `json:"state"`
+}
+
+// 定义一个结构体，用于描述 Swagger 文档中的用户列表和分页信息
+type UserSwagger struct {
+	List  []*User
+	Pager *app.Pager
+}
+
+func (u User) TableName() string {
+	return "blog_user"
+}
+
+func (u User) Create(db *gorm.DB) (*User, error) {
+	if err := db.Create(&u).Error; err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+// 只更新 values 中给出的字段
+func (u User) Update(db *gorm.DB, values interface{}) error {
+	return db.Model(&User{}).Where("id = ? AND is_del = ?", u.ID, 0).Updates(values).Error
+}
+
+func (u User) Get(db *gorm.DB) (User, error) {
+	var user User
+	err := db.Where("id = ? AND is_del = ?", u.ID, 0).First(&user).Error
+	if err != nil {
+		return user, err
+	}
+	return user, nil
+}
+
+func (u User) GetByUsername(db *gorm.DB) (User, error) {
+	var user User
+	err := db.Where("username = ? AND is_del = ?", u.Username, 0).First(&user).Error
+	if err != nil {
+		return user, err
+	}
+	return user, nil
+}
+
+func (u User) List(db *gorm.DB, pageOffset, pageSize int) ([]*User, error) {
+	var users []*User
+	if pageOffset >= 0 && pageSize > 0 {
+		db = db.Offset(pageOffset).Limit(pageSize)
+	}
+	err := u.scope(db).Order("id").Find(&users).Error
+	if err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+func (u User) Count(db *gorm.DB) (int64, error) {
+	var count int64
+	err := u.scope(db.Model(&User{})).Count(&count).Error
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (u User) scope(db *gorm.DB) *gorm.DB {
+	if u.Role != "" {
+		db = db.Where("role = ?", u.Role)
+	}
+	return db.Where("is_del = ?", 0)
+}
@@ -0,0 +1,48 @@
+package api
+
+import (
+	"blog-service/global"
+	"blog-service/internal/service"
+	"blog-service/pkg/app"
+	"blog-service/pkg/errcode"
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary 获取鉴权Token
+// @Produce  json
+// @Param app_key formData string false "AppKey，与 username 二选一"
+// @Param app_secret formData string false "AppSecret"
+// @Param username formData string false "用户名"
+// @Param password formData string false "密码"
+// @Success 200 {string} string "成功"
+// @Failure 400 {object} errcode.Error "请求错误"
+// @Failure 401 {object} errcode.Error "鉴权失败"
+// @Failure 500 {object} errcode.Error "内部错误"
+// @Router /auth [post]
+func GetAuth(c *gin.Context) {
+	param := service.AuthRequest{}
+	response := app.NewResponse(c)
+	valid, errs := app.BindAndValid(c, &param)
+	if !valid {
+		global.Logger.WithContext(c.Request.Context()).Errorf("app.BindAndValid errs: %v", errs)
+		response.ToErrorResponse(errcode.InvalidParams.WithDetails(errs.Errors()...))
+		return
+	}
+	svc := service.New(c.Request.Context())
+	principal, err := svc.Authenticate(&param)
+	if err != nil {
+		global.Logger.WithContext(c.Request.Context()).Errorf("svc.Authenticate err: %v", err)
+		response.ToErrorResponse(errcode.UnauthorizedAuthNotExist)
+		return
+	}
+	token, err := app.GenerateToken(principal)
+	if err != nil {
+		global.Logger.WithContext(c.Request.Context()).Errorf("app.GenerateToken err: %v", err)
+		response.ToErrorResponse(errcode.UnauthorizedTokenGenerate)
+		return
+	}
+	response.ToResponse(gin.H{
+		"token": token,
+	})
+	return
+}
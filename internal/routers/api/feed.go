@@ -0,0 +1,112 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"blog-service/global"
+	"blog-service/internal/service"
+	"blog-service/pkg/app"
+	"blog-service/pkg/convert"
+	"blog-service/pkg/errcode"
+	"github.com/gin-gonic/gin"
+)
+
+type Feed struct{}
+
+func NewFeed() Feed {
+	return Feed{}
+}
+
+// @Summary RSS 2.0 订阅源
+// @Produce  xml
+// @Success 200 {string} string "成功"
+// @Success 304 {string} string "未修改"
+// @Router /feed.rss [get]
+func (f Feed) RSS(c *gin.Context) {
+	f.render(c, "application/rss+xml; charset=utf-8", func(feed *service.Feed) (string, error) {
+		return feed.ToRss()
+	})
+}
+
+// @Summary Atom 订阅源
+// @Produce  xml
+// @Success 200 {string} string "成功"
+// @Success 304 {string} string "未修改"
+// @Router /feed.atom [get]
+func (f Feed) Atom(c *gin.Context) {
+	f.render(c, "application/atom+xml; charset=utf-8", func(feed *service.Feed) (string, error) {
+		return feed.ToAtom()
+	})
+}
+
+// @Summary JSON Feed 订阅源
+// @Produce  json
+// @Success 200 {string} string "成功"
+// @Success 304 {string} string "未修改"
+// @Router /feed.json [get]
+func (f Feed) JSON(c *gin.Context) {
+	f.render(c, "application/feed+json; charset=utf-8", func(feed *service.Feed) (string, error) {
+		return feed.ToJSON()
+	})
+}
+
+// 标签路由 /tags/:id/feed.* 与全站订阅源共用处理逻辑，id 为空时生成全站订阅源
+func (f Feed) render(c *gin.Context, contentType string, encode func(*service.Feed) (string, error)) {
+	param := service.FeedRequest{TagID: convert.StrTo(c.Param("id")).MustUInt32()}
+	response := app.NewResponse(c)
+	if c.Param("id") != "" && param.TagID == 0 {
+		response.ToErrorResponse(errcode.InvalidParams.WithDetails("id 必须为正整数"))
+		return
+	}
+	svc := service.New(c.Request.Context())
+	feed, err := svc.GetFeed(&param)
+	if errors.Is(err, service.ErrTagNotFound) {
+		response.ToErrorResponse(errcode.NotFound)
+		return
+	}
+	if err != nil {
+		global.Logger.WithContext(c.Request.Context()).Errorf("svc.GetFeed err: %v", err)
+		response.ToErrorResponse(errcode.ErrorGetFeedFail)
+		return
+	}
+
+	c.Header("ETag", feed.ETag)
+	if !feed.LastModified.IsZero() {
+		c.Header("Last-Modified", feed.LastModified.UTC().Format(http.TimeFormat))
+	}
+	if notModified(c.Request, feed) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	body, err := encode(feed)
+	if err != nil {
+		global.Logger.WithContext(c.Request.Context()).Errorf("feed encode err: %v", err)
+		response.ToErrorResponse(errcode.ErrorGetFeedFail)
+		return
+	}
+	c.Data(http.StatusOK, contentType, []byte(body))
+}
+
+// If-None-Match 优先于 If-Modified-Since，与 RFC 7232 一致
+func notModified(r *http.Request, feed *service.Feed) bool {
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		for _, etag := range strings.Split(inm, ",") {
+			etag = strings.TrimSpace(etag)
+			if etag == "*" || strings.TrimPrefix(etag, "W/") == strings.TrimPrefix(feed.ETag, "W/") {
+				return true
+			}
+		}
+		return false
+	}
+	if feed.LastModified.IsZero() {
+		return false
+	}
+	ims, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+	return !feed.LastModified.Truncate(time.Second).After(ims)
+}
@@ -0,0 +1,88 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"blog-service/global"
+	"blog-service/pkg/health"
+	"github.com/gin-gonic/gin"
+)
+
+// 就绪检查的超时时间
+const readyTimeout = 2 * time.Second
+
+type Health struct{}
+
+func NewHealth() Health {
+	return Health{}
+}
+
+type dbStats struct {
+	MaxOpenConnections int   `json:"max_open_connections"`
+	OpenConnections    int   `json:"open_connections"`
+	InUse              int   `json:"in_use"`
+	Idle               int   `json:"idle"`
+	WaitCount          int64 `json:"wait_count"`
+	WaitDurationMs     int64 `json:"wait_duration_ms"`
+}
+
+// @Summary 存活检查
+// @Produce  json
+// @Success 200 {string} string "成功"
+// @Router /healthz [get]
+func (h Health) Healthz(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// @Summary 就绪检查，检查数据库连接、全文索引和上传目录等已注册的依赖
+// @Produce  json
+// @Success 200 {string} string "成功"
+// @Failure 503 {string} string "服务未就绪"
+// @Router /readyz [get]
+func (h Health) Readyz(c *gin.Context) {
+	if health.IsShuttingDown() {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "shutting_down"})
+		return
+	}
+	ctx, cancel := context.WithTimeout(c.Request.Context(), readyTimeout)
+	defer cancel()
+
+	ready := true
+	dbResult := health.Result{Name: "database"}
+	var stats *dbStats
+	sqlDB, err := global.DBEngine.DB()
+	if err == nil {
+		err = sqlDB.PingContext(ctx)
+		s := sqlDB.Stats()
+		stats = &dbStats{
+			MaxOpenConnections: s.MaxOpenConnections,
+			OpenConnections:    s.OpenConnections,
+			InUse:              s.InUse,
+			Idle:               s.Idle,
+			WaitCount:          s.WaitCount,
+			WaitDurationMs:     s.WaitDuration.Milliseconds(),
+		}
+	}
+	if err != nil {
+		dbResult.Error = err.Error()
+		ready = false
+	}
+	checks := append([]health.Result{dbResult}, health.Check(ctx)...)
+	for _, r := range checks {
+		if !r.OK() {
+			ready = false
+		}
+	}
+
+	status, code := "ok", http.StatusOK
+	if !ready {
+		status, code = "unavailable", http.StatusServiceUnavailable
+	}
+	c.JSON(code, gin.H{
+		"status":   status,
+		"checks":   checks,
+		"db_stats": stats,
+	})
+}
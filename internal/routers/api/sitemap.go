@@ -0,0 +1,64 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"blog-service/global"
+	"blog-service/internal/service"
+	"blog-service/pkg/app"
+	"blog-service/pkg/convert"
+	"blog-service/pkg/errcode"
+	"github.com/gin-gonic/gin"
+)
+
+const xmlContentType = "application/xml; charset=utf-8"
+
+type Sitemap struct{}
+
+func NewSitemap() Sitemap {
+	return Sitemap{}
+}
+
+// @Summary 站点地图，URL 超过 50000 个时返回 sitemap 索引
+// @Produce  xml
+// @Success 200 {string} string "成功"
+// @Router /sitemap.xml [get]
+func (s Sitemap) Index(c *gin.Context) {
+	svc := service.New(c.Request.Context())
+	body, err := svc.GetSitemap()
+	if err != nil {
+		global.Logger.WithContext(c.Request.Context()).Errorf("svc.GetSitemap err: %v", err)
+		app.NewResponse(c).ToErrorResponse(errcode.ErrorGetSitemapFail)
+		return
+	}
+	c.Data(http.StatusOK, xmlContentType, body)
+}
+
+// @Summary 拆分后的站点地图，只在 /sitemap.xml 为索引时提供
+// @Produce  xml
+// @Param page path string true "页码，如 1.xml"
+// @Success 200 {string} string "成功"
+// @Failure 404 {object} errcode.Error "找不到"
+// @Router /sitemaps/{page} [get]
+func (s Sitemap) Page(c *gin.Context) {
+	response := app.NewResponse(c)
+	name, ok := strings.CutSuffix(c.Param("page"), ".xml")
+	if !ok {
+		response.ToErrorResponse(errcode.NotFound)
+		return
+	}
+	svc := service.New(c.Request.Context())
+	body, err := svc.GetSitemapPage(convert.StrTo(name).MustInt())
+	if errors.Is(err, service.ErrSitemapNotFound) {
+		response.ToErrorResponse(errcode.NotFound)
+		return
+	}
+	if err != nil {
+		global.Logger.WithContext(c.Request.Context()).Errorf("svc.GetSitemapPage err: %v", err)
+		response.ToErrorResponse(errcode.ErrorGetSitemapFail)
+		return
+	}
+	c.Data(http.StatusOK, xmlContentType, body)
+}
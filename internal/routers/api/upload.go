@@ -0,0 +1,62 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"blog-service/global"
+	"blog-service/internal/service"
+	"blog-service/pkg/app"
+	"blog-service/pkg/convert"
+	"blog-service/pkg/errcode"
+	"blog-service/pkg/upload"
+	"github.com/gin-gonic/gin"
+)
+
+type Upload struct{}
+
+func NewUpload() Upload {
+	return Upload{}
+}
+
+// @Summary 上传文件
+// @Produce  json
+// @Param file formData file true "文件"
+// @Param type formData int true "文件类型" Enums(1)
+// @Success 200 {object} service.FileInfo "成功"
+// @Failure 400 {object} errcode.Error "请求错误，扩展名、大小或文件内容不符合要求"
+// @Failure 500 {object} errcode.Error "内部错误"
+// @Router /upload/file [post]
+func (u Upload) UploadFile(c *gin.Context) {
+	response := app.NewResponse(c)
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, upload.MaxRequestSize())
+	file, fileHeader, err := c.Request.FormFile("file")
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		response.ToErrorResponse(errcode.InvalidParams.WithDetails("exceeded maximum file limit"))
+		return
+	}
+	if err != nil {
+		response.ToErrorResponse(errcode.InvalidParams.WithDetails(err.Error()))
+		return
+	}
+	defer file.Close()
+	fileType := convert.StrTo(c.PostForm("type")).MustInt()
+	if fileHeader == nil || fileType <= 0 {
+		response.ToErrorResponse(errcode.InvalidParams)
+		return
+	}
+	svc := service.New(c.Request.Context())
+	fileInfo, err := svc.UploadFile(upload.FileType(fileType), file, fileHeader)
+	if errors.Is(err, service.ErrInvalidUpload) {
+		response.ToErrorResponse(errcode.InvalidParams.WithDetails(err.Error()))
+		return
+	}
+	if err != nil {
+		global.Logger.WithContext(c.Request.Context()).Errorf("svc.UploadFile err: %v", err)
+		response.ToErrorResponse(errcode.ErrorUploadFileFail)
+		return
+	}
+	response.ToResponse(fileInfo)
+	return
+}
@@ -1,7 +1,14 @@
 package v1
 
 import (
+	"errors"
+	"net/http"
+	"net/url"
+
+	"blog-service/global"
+	"blog-service/internal/service"
 	"blog-service/pkg/app"
+	"blog-service/pkg/convert"
 	"blog-service/pkg/errcode"
 	"github.com/gin-gonic/gin"
 )
@@ -21,62 +28,254 @@ func NewArticle() Article {
 // @Summary 获取单个文章
 // @Produce  json
 // @Param id path int true "文章ID"
-// @Success 200 {object} model.ArticleSwagger "成功"
+// @Success 200 {object} service.ArticleDetail "成功，content_html 为渲染后的 HTML，toc 为目录"
 // @Failure 400 {object} errcode.Error "请求错误"
+// @Failure 404 {object} errcode.Error "文章不存在"
 // @Failure 500 {object} errcode.Error "内部错误"
 // @Router /api/v1/articles/{id} [get]
 func (a Article) Get(c *gin.Context) {
-	app.NewResponse(c).ToErrorResponse(errcode.ServerError)
+	param := service.ArticleRequest{ID: convert.StrTo(c.Param("id")).MustUInt32()}
+	response := app.NewResponse(c)
+	valid, errs := app.BindAndValid(c, &param)
+	if !valid {
+		global.Logger.WithContext(c.Request.Context()).Errorf("app.BindAndValid errs: %v", errs)
+		response.ToErrorResponse(errcode.InvalidParams.WithDetails(errs.Errors()...))
+		return
+	}
+	svc := service.New(c.Request.Context())
+	article, err := svc.GetArticle(&param)
+	if errors.Is(err, service.ErrArticleNotFound) {
+		response.ToErrorResponse(errcode.NotFound)
+		return
+	}
+	if err != nil {
+		global.Logger.WithContext(c.Request.Context()).Errorf("svc.GetArticle err: %v", err)
+		response.ToErrorResponse(errcode.ErrorGetArticleFail)
+		return
+	}
+	response.ToResponse(article)
 	return
 }
 
 // @Summary 获取多个文章
 // @Produce  json
 // @Param tag_id query int false "标签ID"
-// @Param state query int false "状态" Enums(0, 1) default(1)
+// @Param state query int false "状态 0 草稿、1 已发布、2 待审核、3 定时发布、4 已归档，非已发布状态需要编辑文章的权限，否则按已发布查询" Enums(0, 1, 2, 3, 4) default(1)
 // @Param page query int false "页码"
 // @Param page_size query int false "每页数量"
+// @Param cursor query string false "游标，传入时使用游标分页，首页传空字符串"
+// @Param limit query int false "游标分页每页数量"
 // @Success 200 {object} model.ArticleSwagger "成功"
 // @Failure 400 {object} errcode.Error "请求错误"
 // @Failure 500 {object} errcode.Error "内部错误"
 // @Router /api/v1/articles [get]
-func (a Article) List(c *gin.Context) {}
+func (a Article) List(c *gin.Context) {
+	param := service.ArticleListRequest{}
+	response := app.NewResponse(c)
+	valid, errs := app.BindAndValid(c, &param)
+	if !valid {
+		global.Logger.WithContext(c.Request.Context()).Errorf("app.BindAndValid errs: %v", errs)
+		response.ToErrorResponse(errcode.InvalidParams.WithDetails(errs.Errors()...))
+		return
+	}
+	svc := service.New(c.Request.Context())
+	cursorPager, isCursor, err := app.GetCursorPager(c)
+	if err != nil {
+		global.Logger.WithContext(c.Request.Context()).Errorf("app.GetCursorPager err: %v", err)
+		response.ToErrorResponse(errcode.InvalidParams.WithDetails(err.Error()))
+		return
+	}
+	if isCursor {
+		articles, err := svc.GetArticleCursorList(&param, cursorPager)
+		if err != nil {
+			global.Logger.WithContext(c.Request.Context()).Errorf("svc.GetArticleCursorList err: %v", err)
+			response.ToErrorResponse(errcode.ErrorGetArticlesFail)
+			return
+		}
+		response.ToResponseList(articles, 0)
+		return
+	}
+	pager := app.Pager{Page: app.GetPage(c), PageSize: app.GetPageSize(c)}
+	articles, totalRows, err := svc.GetArticleList(&param, &pager)
+	if err != nil {
+		global.Logger.WithContext(c.Request.Context()).Errorf("svc.GetArticleList err: %v", err)
+		response.ToErrorResponse(errcode.ErrorGetArticlesFail)
+		return
+	}
+	response.ToResponseList(articles, totalRows)
+	return
+}
 
 // @Summary 新增文章
 // @Produce  json
-// @Param tag_id body int false "标签ID"
+// @Param tag_ids body []int true "标签ID列表，标签必须存在且已启用"
 // @Param title body string true "标题" minlength(3) maxlength(100)
 // @Param desc body string false "简述" maxlength(255)
 // @Param content body string false "内容" maxlength(4294967295)
 // @Param cover_image_url body string false "封面图" maxlength(255)
-// @Param state body int false "状态" Enums(0, 1) default(1)
-// @Param created_by body string true "创建者" minlength(3) maxlength(100)
+// @Param state body int false "状态 0 草稿、1 已发布、2 待审核、3 定时发布，未传时有发布权限则发布，否则保存为草稿" Enums(0, 1, 2, 3)
+// @Param publish_at body int false "定时发布的时间戳，state 为 3 时必填且晚于当前时间"
 // @Success 200 {object} model.ArticleSwagger "成功"
 // @Failure 400 {object} errcode.Error "请求错误"
+// @Failure 403 {object} errcode.Error "没有权限"
 // @Failure 500 {object} errcode.Error "内部错误"
 // @Router /api/v1/articles [post]
-func (a Article) Create(c *gin.Context) {}
+func (a Article) Create(c *gin.Context) {
+	param := service.CreateArticleRequest{}
+	response := app.NewResponse(c)
+	valid, errs := app.BindAndValid(c, &param)
+	if !valid {
+		global.Logger.WithContext(c.Request.Context()).Errorf("app.BindAndValid errs: %v", errs)
+		response.ToErrorResponse(errcode.InvalidParams.WithDetails(errs.Errors()...))
+		return
+	}
+	svc := service.New(c.Request.Context())
+	err := svc.CreateArticle(&param)
+	switch {
+	case errors.Is(err, service.ErrForbidden):
+		response.ToErrorResponse(errcode.Forbidden)
+		return
+	case errors.Is(err, service.ErrInvalidTransition), errors.Is(err, service.ErrInvalidPublishAt),
+		errors.Is(err, service.ErrInvalidTags):
+		response.ToErrorResponse(errcode.InvalidParams.WithDetails(err.Error()))
+		return
+	}
+	if err != nil {
+		global.Logger.WithContext(c.Request.Context()).Errorf("svc.CreateArticle err: %v", err)
+		response.ToErrorResponse(errcode.ErrorCreateArticleFail)
+		return
+	}
+	response.ToResponse(gin.H{})
+	return
+}
 
 // @Summary 更新文章
 // @Produce  json
 // @Param id path int true "文章ID"
-// @Param tag_id body int false "标签ID"
+// @Param tag_ids body []int false "标签ID列表，传入时整体替换文章标签，标签必须存在且已启用"
 // @Param title body string false "标题" minlength(3) maxlength(100)
 // @Param desc body string false "简述" maxlength(255)
 // @Param content body string false "内容" maxlength(4294967295)
 // @Param cover_image_url body string false "封面图" maxlength(255)
-// @Param modified_by body string true "修改者" minlength(3) maxlength(100)
+// @Param state body int false "状态，未传时保持不变，允许的状态变更见 README" Enums(0, 1, 2, 3, 4)
+// @Param publish_at body int false "定时发布的时间戳，文章已是定时发布时传入可修改计划发布时间"
 // @Success 200 {object} model.ArticleSwagger "成功"
 // @Failure 400 {object} errcode.Error "请求错误"
+// @Failure 403 {object} errcode.Error "没有权限"
+// @Failure 404 {object} errcode.Error "文章不存在"
 // @Failure 500 {object} errcode.Error "内部错误"
 // @Router /api/v1/articles/{id} [put]
-func (a Article) Update(c *gin.Context) {}
+func (a Article) Update(c *gin.Context) {
+	param := service.UpdateArticleRequest{ID: convert.StrTo(c.Param("id")).MustUInt32()}
+	response := app.NewResponse(c)
+	valid, errs := app.BindAndValid(c, &param)
+	if !valid {
+		global.Logger.WithContext(c.Request.Context()).Errorf("app.BindAndValid errs: %v", errs)
+		response.ToErrorResponse(errcode.InvalidParams.WithDetails(errs.Errors()...))
+		return
+	}
+	svc := service.New(c.Request.Context())
+	err := svc.UpdateArticle(&param)
+	switch {
+	case errors.Is(err, service.ErrForbidden):
+		response.ToErrorResponse(errcode.Forbidden)
+		return
+	case errors.Is(err, service.ErrInvalidTransition), errors.Is(err, service.ErrInvalidPublishAt),
+		errors.Is(err, service.ErrInvalidTags):
+		response.ToErrorResponse(errcode.InvalidParams.WithDetails(err.Error()))
+		return
+	case errors.Is(err, service.ErrArticleNotFound):
+		response.ToErrorResponse(errcode.NotFound)
+		return
+	}
+	if err != nil {
+		global.Logger.WithContext(c.Request.Context()).Errorf("svc.UpdateArticle err: %v", err)
+		response.ToErrorResponse(errcode.ErrorUpdateArticleFail)
+		return
+	}
+	response.ToResponse(gin.H{})
+	return
+}
 
 // @Summary 删除文章
 // @Produce  json
 // @Param id path int true "文章ID"
 // @Success 200 {string} string "成功"
 // @Failure 400 {object} errcode.Error "请求错误"
+// @Failure 403 {object} errcode.Error "没有权限"
+// @Failure 404 {object} errcode.Error "文章不存在"
 // @Failure 500 {object} errcode.Error "内部错误"
 // @Router /api/v1/articles/{id} [delete]
-func (a Article) Delete(c *gin.Context) {}
+func (a Article) Delete(c *gin.Context) {
+	param := service.DeleteArticleRequest{ID: convert.StrTo(c.Param("id")).MustUInt32()}
+	response := app.NewResponse(c)
+	valid, errs := app.BindAndValid(c, &param)
+	if !valid {
+		global.Logger.WithContext(c.Request.Context()).Errorf("app.BindAndValid errs: %v", errs)
+		response.ToErrorResponse(errcode.InvalidParams.WithDetails(errs.Errors()...))
+		return
+	}
+	svc := service.New(c.Request.Context())
+	err := svc.DeleteArticle(&param)
+	switch {
+	case errors.Is(err, service.ErrForbidden):
+		response.ToErrorResponse(errcode.Forbidden)
+		return
+	case errors.Is(err, service.ErrArticleNotFound):
+		response.ToErrorResponse(errcode.NotFound)
+		return
+	}
+	if err != nil {
+		global.Logger.WithContext(c.Request.Context()).Errorf("svc.DeleteArticle err: %v", err)
+		response.ToErrorResponse(errcode.ErrorDeleteArticleFail)
+		return
+	}
+	response.ToResponse(gin.H{})
+	return
+}
+
+// @Summary 按 slug 获取单个文章，旧的 slug 以 301 重定向到当前 slug
+// @Produce  json
+// @Param slug path string true "文章 slug"
+// @Param state query int false "状态 0 草稿、1 已发布、2 待审核、3 定时发布、4 已归档，非已发布状态需要编辑文章的权限，否则按已发布查询" Enums(0, 1, 2, 3, 4) default(1)
+// @Success 200 {object} service.ArticleDetail "成功"
+// @Success 301 {string} string "slug 已变更，Location 为新地址"
+// @Failure 400 {object} errcode.Error "请求错误"
+// @Failure 404 {object} errcode.Error "文章不存在"
+// @Failure 500 {object} errcode.Error "内部错误"
+// @Router /api/v1/articles/by-slug/{slug} [get]
+func (a Article) GetBySlug(c *gin.Context) {
+	param := service.ArticleSlugRequest{Slug: c.Param("slug")}
+	response := app.NewResponse(c)
+	valid, errs := app.BindAndValid(c, &param)
+	if !valid {
+		global.Logger.WithContext(c.Request.Context()).Errorf("app.BindAndValid errs: %v", errs)
+		response.ToErrorResponse(errcode.InvalidParams.WithDetails(errs.Errors()...))
+		return
+	}
+	svc := service.New(c.Request.Context())
+	article, redirect, err := svc.GetArticleBySlug(&param)
+	if errors.Is(err, service.ErrArticleNotFound) {
+		response.ToErrorResponse(errcode.NotFound)
+		return
+	}
+	if err != nil {
+		global.Logger.WithContext(c.Request.Context()).Errorf("svc.GetArticleBySlug err: %v", err)
+		response.ToErrorResponse(errcode.ErrorGetArticleFail)
+		return
+	}
+	if redirect != "" {
+		redirectToSlug(c, "/api/v1/articles/by-slug/", redirect)
+		return
+	}
+	response.ToResponse(article)
+}
+
+// 永久重定向到新的 slug，保留原有的查询参数
+func redirectToSlug(c *gin.Context, prefix, slug string) {
+	location := prefix + url.PathEscape(slug)
+	if c.Request.URL.RawQuery != "" {
+		location += "?" + c.Request.URL.RawQuery
+	}
+	c.Redirect(http.StatusMovedPermanently, location)
+}
@@ -0,0 +1,163 @@
+package v1
+
+import (
+	"errors"
+
+	"blog-service/global"
+	"blog-service/internal/service"
+	"blog-service/pkg/app"
+	"blog-service/pkg/convert"
+	"blog-service/pkg/errcode"
+	"github.com/gin-gonic/gin"
+)
+
+type ArticleRevision struct{}
+
+func NewArticleRevision() ArticleRevision {
+	return ArticleRevision{}
+}
+
+// @Summary 获取文章的修订列表，不包含正文，最新的修订在前
+// @Produce  json
+// @Param id path int true "文章ID"
+// @Param page query int false "页码"
+// @Param page_size query int false "每页数量"
+// @Success 200 {object} model.ArticleRevisionSwagger "成功"
+// @Failure 400 {object} errcode.Error "请求错误"
+// @Failure 403 {object} errcode.Error "没有权限"
+// @Failure 404 {object} errcode.Error "文章不存在"
+// @Failure 500 {object} errcode.Error "内部错误"
+// @Router /api/v1/articles/{id}/revisions [get]
+func (r ArticleRevision) List(c *gin.Context) {
+	param := service.ArticleRevisionListRequest{ArticleID: convert.StrTo(c.Param("id")).MustUInt32()}
+	response := app.NewResponse(c)
+	valid, errs := app.BindAndValid(c, &param)
+	if !valid {
+		global.Logger.WithContext(c.Request.Context()).Errorf("app.BindAndValid errs: %v", errs)
+		response.ToErrorResponse(errcode.InvalidParams.WithDetails(errs.Errors()...))
+		return
+	}
+	svc := service.New(c.Request.Context())
+	pager := app.Pager{Page: app.GetPage(c), PageSize: app.GetPageSize(c)}
+	totalRows, err := svc.CountArticleRevision(&param)
+	if err != nil {
+		global.Logger.WithContext(c.Request.Context()).Errorf("svc.CountArticleRevision err: %v", err)
+		response.ToErrorResponse(revisionError(err, errcode.ErrorGetRevisionListFail))
+		return
+	}
+	revisions, err := svc.GetArticleRevisionList(&param, &pager)
+	if err != nil {
+		global.Logger.WithContext(c.Request.Context()).Errorf("svc.GetArticleRevisionList err: %v", err)
+		response.ToErrorResponse(revisionError(err, errcode.ErrorGetRevisionListFail))
+		return
+	}
+	response.ToResponseList(revisions, totalRows)
+}
+
+// @Summary 获取单个修订
+// @Produce  json
+// @Param id path int true "文章ID"
+// @Param revision_id path int true "修订ID"
+// @Success 200 {object} model.ArticleRevision "成功"
+// @Failure 400 {object} errcode.Error "请求错误"
+// @Failure 403 {object} errcode.Error "没有权限"
+// @Failure 404 {object} errcode.Error "文章或修订不存在"
+// @Failure 500 {object} errcode.Error "内部错误"
+// @Router /api/v1/articles/{id}/revisions/{revision_id} [get]
+func (r ArticleRevision) Get(c *gin.Context) {
+	param := service.ArticleRevisionRequest{
+		ArticleID: convert.StrTo(c.Param("id")).MustUInt32(),
+		ID:        convert.StrTo(c.Param("revision_id")).MustUInt32(),
+	}
+	response := app.NewResponse(c)
+	valid, errs := app.BindAndValid(c, &param)
+	if !valid {
+		global.Logger.WithContext(c.Request.Context()).Errorf("app.BindAndValid errs: %v", errs)
+		response.ToErrorResponse(errcode.InvalidParams.WithDetails(errs.Errors()...))
+		return
+	}
+	svc := service.New(c.Request.Context())
+	revision, err := svc.GetArticleRevision(&param)
+	if err != nil {
+		global.Logger.WithContext(c.Request.Context()).Errorf("svc.GetArticleRevision err: %v", err)
+		response.ToErrorResponse(revisionError(err, errcode.ErrorGetRevisionFail))
+		return
+	}
+	response.ToResponse(revision)
+}
+
+// @Summary 比较两个修订，按字段返回 unified 格式的行级差异
+// @Produce  json
+// @Param id path int true "文章ID"
+// @Param revision_id path int true "修订ID"
+// @Param base query int false "作为比较基准的修订ID，默认为上一个修订"
+// @Success 200 {object} service.ArticleRevisionDiff "成功"
+// @Failure 400 {object} errcode.Error "请求错误"
+// @Failure 403 {object} errcode.Error "没有权限"
+// @Failure 404 {object} errcode.Error "文章或修订不存在"
+// @Failure 500 {object} errcode.Error "内部错误"
+// @Router /api/v1/articles/{id}/revisions/{revision_id}/diff [get]
+func (r ArticleRevision) Diff(c *gin.Context) {
+	param := service.DiffArticleRevisionRequest{
+		ArticleID: convert.StrTo(c.Param("id")).MustUInt32(),
+		ID:        convert.StrTo(c.Param("revision_id")).MustUInt32(),
+	}
+	response := app.NewResponse(c)
+	valid, errs := app.BindAndValid(c, &param)
+	if !valid {
+		global.Logger.WithContext(c.Request.Context()).Errorf("app.BindAndValid errs: %v", errs)
+		response.ToErrorResponse(errcode.InvalidParams.WithDetails(errs.Errors()...))
+		return
+	}
+	svc := service.New(c.Request.Context())
+	diff, err := svc.DiffArticleRevision(&param)
+	if err != nil {
+		global.Logger.WithContext(c.Request.Context()).Errorf("svc.DiffArticleRevision err: %v", err)
+		response.ToErrorResponse(revisionError(err, errcode.ErrorDiffRevisionFail))
+		return
+	}
+	response.ToResponse(diff)
+}
+
+// @Summary 恢复修订，以修订的内容、封面和标签更新文章并产生一个新的修订，状态不变
+// @Produce  json
+// @Param id path int true "文章ID"
+// @Param revision_id path int true "修订ID"
+// @Success 200 {string} string "成功"
+// @Failure 400 {object} errcode.Error "请求错误"
+// @Failure 403 {object} errcode.Error "没有权限"
+// @Failure 404 {object} errcode.Error "文章或修订不存在"
+// @Failure 500 {object} errcode.Error "内部错误"
+// @Router /api/v1/articles/{id}/revisions/{revision_id}/restore [post]
+func (r ArticleRevision) Restore(c *gin.Context) {
+	param := service.ArticleRevisionRequest{
+		ArticleID: convert.StrTo(c.Param("id")).MustUInt32(),
+		ID:        convert.StrTo(c.Param("revision_id")).MustUInt32(),
+	}
+	response := app.NewResponse(c)
+	valid, errs := app.BindAndValid(c, &param)
+	if !valid {
+		global.Logger.WithContext(c.Request.Context()).Errorf("app.BindAndValid errs: %v", errs)
+		response.ToErrorResponse(errcode.InvalidParams.WithDetails(errs.Errors()...))
+		return
+	}
+	svc := service.New(c.Request.Context())
+	err := svc.RestoreArticleRevision(&param)
+	if err != nil {
+		global.Logger.WithContext(c.Request.Context()).Errorf("svc.RestoreArticleRevision err: %v", err)
+		response.ToErrorResponse(revisionError(err, errcode.ErrorRestoreRevisionFail))
+		return
+	}
+	response.ToResponse(gin.H{})
+}
+
+// 权限不足和文章、修订不存在有对应的错误码，其他错误返回 fallback
+func revisionError(err error, fallback *errcode.Error) *errcode.Error {
+	switch {
+	case errors.Is(err, service.ErrForbidden):
+		return errcode.Forbidden
+	case errors.Is(err, service.ErrArticleNotFound), errors.Is(err, service.ErrRevisionNotFound):
+		return errcode.NotFound
+	}
+	return fallback
+}
@@ -0,0 +1,173 @@
+package v1
+
+import (
+	"errors"
+
+	"blog-service/global"
+	"blog-service/internal/service"
+	"blog-service/pkg/app"
+	"blog-service/pkg/convert"
+	"blog-service/pkg/errcode"
+	"github.com/gin-gonic/gin"
+)
+
+type Comment struct{}
+
+func NewComment() Comment {
+	return Comment{}
+}
+
+// @Summary 获取文章的评论，只返回已通过审核的评论，按时间正序排列
+// @Produce  json
+// @Param id path int true "文章ID"
+// @Param page query int false "页码"
+// @Param page_size query int false "每页数量"
+// @Success 200 {object} model.CommentSwagger "成功"
+// @Failure 400 {object} errcode.Error "请求错误"
+// @Failure 404 {object} errcode.Error "文章不存在或未发布"
+// @Failure 500 {object} errcode.Error "内部错误"
+// @Router /api/v1/articles/{id}/comments [get]
+func (cm Comment) List(c *gin.Context) {
+	param := service.CommentListRequest{ArticleID: convert.StrTo(c.Param("id")).MustUInt32()}
+	response := app.NewResponse(c)
+	valid, errs := app.BindAndValid(c, &param)
+	if !valid {
+		global.Logger.WithContext(c.Request.Context()).Errorf("app.BindAndValid errs: %v", errs)
+		response.ToErrorResponse(errcode.InvalidParams.WithDetails(errs.Errors()...))
+		return
+	}
+	svc := service.New(c.Request.Context())
+	pager := app.Pager{Page: app.GetPage(c), PageSize: app.GetPageSize(c)}
+	totalRows, err := svc.CountComment(&param)
+	if errors.Is(err, service.ErrArticleNotFound) {
+		response.ToErrorResponse(errcode.NotFound)
+		return
+	}
+	if err != nil {
+		global.Logger.WithContext(c.Request.Context()).Errorf("svc.CountComment err: %v", err)
+		response.ToErrorResponse(errcode.ErrorCountCommentFail)
+		return
+	}
+	comments, err := svc.GetCommentList(&param, &pager)
+	if errors.Is(err, service.ErrArticleNotFound) {
+		response.ToErrorResponse(errcode.NotFound)
+		return
+	}
+	if err != nil {
+		global.Logger.WithContext(c.Request.Context()).Errorf("svc.GetCommentList err: %v", err)
+		response.ToErrorResponse(errcode.ErrorGetCommentListFail)
+		return
+	}
+	response.ToResponseList(comments, totalRows)
+}
+
+// @Summary 发表评论，评论需要审核后才会展示
+// @Produce  json
+// @Param id path int true "文章ID"
+// @Param parent_id body int false "回复的评论ID"
+// @Param author body string true "昵称" minlength(2) maxlength(100)
+// @Param email body string false "邮箱" maxlength(255)
+// @Param content body string true "内容" maxlength(2000)
+// @Success 200 {object} model.Comment "成功"
+// @Failure 400 {object} errcode.Error "请求错误"
+// @Failure 404 {object} errcode.Error "文章不存在"
+// @Failure 500 {object} errcode.Error "内部错误"
+// @Router /api/v1/articles/{id}/comments [post]
+func (cm Comment) Create(c *gin.Context) {
+	param := service.CreateCommentRequest{ArticleID: convert.StrTo(c.Param("id")).MustUInt32()}
+	response := app.NewResponse(c)
+	valid, errs := app.BindAndValid(c, &param)
+	if !valid {
+		global.Logger.WithContext(c.Request.Context()).Errorf("app.BindAndValid errs: %v", errs)
+		response.ToErrorResponse(errcode.InvalidParams.WithDetails(errs.Errors()...))
+		return
+	}
+	svc := service.New(c.Request.Context())
+	comment, err := svc.CreateComment(&param)
+	switch {
+	case errors.Is(err, service.ErrArticleNotFound):
+		response.ToErrorResponse(errcode.NotFound)
+		return
+	case errors.Is(err, service.ErrInvalidParent):
+		response.ToErrorResponse(errcode.InvalidParams.WithDetails(err.Error()))
+		return
+	case err != nil:
+		global.Logger.WithContext(c.Request.Context()).Errorf("svc.CreateComment err: %v", err)
+		response.ToErrorResponse(errcode.ErrorCreateCommentFail)
+		return
+	}
+	response.ToResponse(comment)
+}
+
+// @Summary 审核列表，按审核状态列出评论
+// @Produce  json
+// @Param article_id query int false "文章ID"
+// @Param state query int false "审核状态 0 待审核、1 已通过、2 垃圾评论、3 已删除" Enums(0, 1, 2, 3) default(0)
+// @Param page query int false "页码"
+// @Param page_size query int false "每页数量"
+// @Success 200 {object} model.CommentSwagger "成功"
+// @Failure 400 {object} errcode.Error "请求错误"
+// @Failure 500 {object} errcode.Error "内部错误"
+// @Router /api/v1/comments [get]
+func (cm Comment) ModerationList(c *gin.Context) {
+	param := service.ModerationCommentListRequest{}
+	response := app.NewResponse(c)
+	valid, errs := app.BindAndValid(c, &param)
+	if !valid {
+		global.Logger.WithContext(c.Request.Context()).Errorf("app.BindAndValid errs: %v", errs)
+		response.ToErrorResponse(errcode.InvalidParams.WithDetails(errs.Errors()...))
+		return
+	}
+	svc := service.New(c.Request.Context())
+	pager := app.Pager{Page: app.GetPage(c), PageSize: app.GetPageSize(c)}
+	totalRows, err := svc.CountModerationComment(&param)
+	if err != nil {
+		global.Logger.WithContext(c.Request.Context()).Errorf("svc.CountModerationComment err: %v", err)
+		response.ToErrorResponse(errcode.ErrorCountCommentFail)
+		return
+	}
+	comments, err := svc.GetModerationCommentList(&param, &pager)
+	if err != nil {
+		global.Logger.WithContext(c.Request.Context()).Errorf("svc.GetModerationCommentList err: %v", err)
+		response.ToErrorResponse(errcode.ErrorGetCommentListFail)
+		return
+	}
+	response.ToResponseList(comments, totalRows)
+}
+
+// @Summary 审核评论
+// @Produce  json
+// @Param id path int true "评论ID"
+// @Param state body int true "审核状态 0 待审核、1 已通过、2 垃圾评论、3 已删除" Enums(0, 1, 2, 3)
+// @Success 200 {string} string "成功"
+// @Failure 400 {object} errcode.Error "请求错误"
+// @Failure 403 {object} errcode.Error "没有权限"
+// @Failure 404 {object} errcode.Error "评论不存在"
+// @Failure 500 {object} errcode.Error "内部错误"
+// @Router /api/v1/comments/{id}/state [patch]
+func (cm Comment) UpdateState(c *gin.Context) {
+	param := service.UpdateCommentStateRequest{ID: convert.StrTo(c.Param("id")).MustUInt32()}
+	response := app.NewResponse(c)
+	valid, errs := app.BindAndValid(c, &param)
+	if !valid {
+		global.Logger.WithContext(c.Request.Context()).Errorf("app.BindAndValid errs: %v", errs)
+		response.ToErrorResponse(errcode.InvalidParams.WithDetails(errs.Errors()...))
+		return
+	}
+	svc := service.New(c.Request.Context())
+	err := svc.UpdateCommentState(&param)
+	switch {
+	case errors.Is(err, service.ErrForbidden):
+		response.ToErrorResponse(errcode.Forbidden)
+		return
+	case errors.Is(err, service.ErrCommentNotFound):
+		response.ToErrorResponse(errcode.NotFound)
+		return
+	}
+	if err != nil {
+		global.Logger.WithContext(c.Request.Context()).Errorf("svc.UpdateCommentState err: %v", err)
+		response.ToErrorResponse(errcode.ErrorUpdateCommentFail)
+		return
+	}
+	response.ToResponse(gin.H{})
+}
@@ -0,0 +1,52 @@
+package v1
+
+import (
+	"errors"
+
+	"blog-service/global"
+	"blog-service/internal/service"
+	"blog-service/pkg/app"
+	"blog-service/pkg/errcode"
+	"blog-service/pkg/search"
+	"github.com/gin-gonic/gin"
+)
+
+type Search struct{}
+
+func NewSearch() Search {
+	return Search{}
+}
+
+// @Summary 全文搜索已发布的文章，按相关度排序
+// @Produce  json
+// @Param q query string true "关键词，支持 \"短语\"、+必须、-排除、title:字段 等语法" maxlength(200)
+// @Param tag query string false "只搜索该标签下的文章"
+// @Param page query int false "页码"
+// @Param page_size query int false "每页数量"
+// @Success 200 {object} service.SearchResult "成功"
+// @Failure 400 {object} errcode.Error "请求错误"
+// @Failure 500 {object} errcode.Error "内部错误"
+// @Router /api/v1/search [get]
+func (s Search) Search(c *gin.Context) {
+	param := service.SearchRequest{}
+	response := app.NewResponse(c)
+	valid, errs := app.BindAndValid(c, &param)
+	if !valid {
+		global.Logger.WithContext(c.Request.Context()).Errorf("app.BindAndValid errs: %v", errs)
+		response.ToErrorResponse(errcode.InvalidParams.WithDetails(errs.Errors()...))
+		return
+	}
+	svc := service.New(c.Request.Context())
+	pager := app.Pager{Page: app.GetPage(c), PageSize: app.GetPageSize(c)}
+	result, err := svc.Search(&param, &pager)
+	if errors.Is(err, search.ErrInvalidQuery) {
+		response.ToErrorResponse(errcode.InvalidParams.WithDetails(err.Error()))
+		return
+	}
+	if err != nil {
+		global.Logger.WithContext(c.Request.Context()).Errorf("svc.Search err: %v", err)
+		response.ToErrorResponse(errcode.ErrorSearchFail)
+		return
+	}
+	response.ToResponse(result)
+}
@@ -1,6 +1,8 @@
 package v1
 
 import (
+	"errors"
+
 	"blog-service/global"
 	"blog-service/internal/service"
 	"blog-service/pkg/app"
@@ -23,6 +25,8 @@ func (t Tag) Get(c *gin.Context) {}
 // @Param state query int false "状态" Enums(0, 1) default(1)
 // @Param page query int false "页码"
 // @Param page_size query int false "每页数量"
+// @Param cursor query string false "游标，传入时使用游标分页，首页传空字符串"
+// @Param limit query int false "游标分页每页数量"
 // @Success 200 {object} model.TagSwagger "成功"
 // @Failure 400 {object} errcode.Error "请求错误"
 // @Failure 500 {object} errcode.Error "内部错误"
@@ -33,26 +37,42 @@ func (t Tag) List(c *gin.Context) {
 	//	State uint8  `form:"state,default=1" binding:"oneof=0 1"`
 	//}{}
 	param := service.TagListRequest{}
-	response := app.Response{c}
+	response := app.Response{Ctx: c}
 	valid, errs := app.BindAndValid(c, &param)
 	if !valid {
-		global.Logger.Errorf("app.BindAndValid errs: %v", errs)
+		global.Logger.WithContext(c.Request.Context()).Errorf("app.BindAndValid errs: %v", errs)
 		//errRsp := errcode.InvalidParams.WithDetails(errs.Errors()...)
 		//response.ToErrorResponse(errRsp)
 		response.ToErrorResponse(errcode.InvalidParams.WithDetails(errs.Errors()...)) // 优化
 		return
 	}
 	svc := service.New(c.Request.Context())
+	cursorPager, isCursor, err := app.GetCursorPager(c)
+	if err != nil {
+		global.Logger.WithContext(c.Request.Context()).Errorf("app.GetCursorPager err: %v", err)
+		response.ToErrorResponse(errcode.InvalidParams.WithDetails(err.Error()))
+		return
+	}
+	if isCursor {
+		tags, err := svc.GetTagCursorList(&param, cursorPager)
+		if err != nil {
+			global.Logger.WithContext(c.Request.Context()).Errorf("svc.GetTagCursorList err: %v", err)
+			response.ToErrorResponse(errcode.ErrorGetTagListFail)
+			return
+		}
+		response.ToResponseList(tags, 0)
+		return
+	}
 	pager := app.Pager{Page: app.GetPage(c), PageSize: app.GetPageSize(c)}
 	totalRows, err := svc.CountTag(&service.CountTagRequest{Name: param.Name, State: param.State})
 	if err != nil {
-		global.Logger.Errorf("svc.CountTag err: %v", err)
+		global.Logger.WithContext(c.Request.Context()).Errorf("svc.CountTag err: %v", err)
 		response.ToErrorResponse(errcode.ErrorCountTagFail)
 		return
 	}
 	tags, err := svc.GetTagList(&param, &pager)
 	if err != nil {
-		global.Logger.Errorf("svc.GetTagList err: %v", err)
+		global.Logger.WithContext(c.Request.Context()).Errorf("svc.GetTagList err: %v", err)
 		response.ToErrorResponse(errcode.ErrorGetTagListFail)
 		return
 	}
@@ -65,24 +85,28 @@ func (t Tag) List(c *gin.Context) {
 // @Produce  json
 // @Param name body string true "标签名称" minlength(3) maxlength(100)
 // @Param state body int false "状态" Enums(0, 1) default(1)
-// @Param created_by body string true "创建者" minlength(3) maxlength(100)
 // @Success 200 {object} model.TagSwagger "成功"
 // @Failure 400 {object} errcode.Error "请求错误"
+// @Failure 403 {object} errcode.Error "没有权限"
 // @Failure 500 {object} errcode.Error "内部错误"
 // @Router /api/v1/tags [post]
 func (t Tag) Create(c *gin.Context) {
 	param := service.CreateTagRequest{}
-	response := app.Response{c}
+	response := app.Response{Ctx: c}
 	valid, errs := app.BindAndValid(c, &param)
 	if !valid {
-		global.Logger.Errorf("app.BindAndValid errs: %v", errs)
+		global.Logger.WithContext(c.Request.Context()).Errorf("app.BindAndValid errs: %v", errs)
 		response.ToErrorResponse(errcode.InvalidParams.WithDetails(errs.Errors()...))
 		return
 	}
 	svc := service.New(c.Request.Context())
 	err := svc.CreateTag(&param)
+	if errors.Is(err, service.ErrForbidden) {
+		response.ToErrorResponse(errcode.Forbidden)
+		return
+	}
 	if err != nil {
-		global.Logger.Errorf("svc.CreateTag err: %v", err)
+		global.Logger.WithContext(c.Request.Context()).Errorf("svc.CreateTag err: %v", err)
 		response.ToErrorResponse(errcode.ErrorCreateTagFail)
 		return
 	}
@@ -95,9 +119,10 @@ func (t Tag) Create(c *gin.Context) {
 // @Param id path int true "标签ID"
 // @Param name body string false "标签名称" minlength(3) maxlength(100)
 // @Param state body int false "状态" Enums(0, 1) default(1)
-// @Param modified_by body string true "修改者" minlength(3) maxlength(100)
 // @Success 200 {object} model.TagSwagger "成功"
 // @Failure 400 {object} errcode.Error "请求错误"
+// @Failure 403 {object} errcode.Error "没有权限"
+// @Failure 404 {object} errcode.Error "标签不存在"
 // @Failure 500 {object} errcode.Error "内部错误"
 // @Router /api/v1/tags/{id} [put]
 func (t Tag) Update(c *gin.Context) {
@@ -107,10 +132,25 @@ func (t Tag) Update(c *gin.Context) {
 	response := app.NewResponse(c)
 	valid, errs := app.BindAndValid(c, &param)
 	if !valid {
-		global.Logger.Errorf("app.BindAndValid errs: %v", errs)
+		global.Logger.WithContext(c.Request.Context()).Errorf("app.BindAndValid errs: %v", errs)
 		response.ToErrorResponse(errcode.InvalidParams.WithDetails(errs.Errors()...))
 		return
 	}
+	svc := service.New(c.Request.Context())
+	err := svc.UpdateTag(&param)
+	switch {
+	case errors.Is(err, service.ErrForbidden):
+		response.ToErrorResponse(errcode.Forbidden)
+		return
+	case errors.Is(err, service.ErrTagNotFound):
+		response.ToErrorResponse(errcode.NotFound)
+		return
+	}
+	if err != nil {
+		global.Logger.WithContext(c.Request.Context()).Errorf("svc.UpdateTag err: %v", err)
+		response.ToErrorResponse(errcode.ErrorUpdateTagFail)
+		return
+	}
 	response.ToResponse(gin.H{})
 	return
 }
@@ -120,6 +160,7 @@ func (t Tag) Update(c *gin.Context) {
 // @Param id path int true "标签ID"
 // @Success 200 {string} string "成功"
 // @Failure 400 {object} errcode.Error "请求错误"
+// @Failure 403 {object} errcode.Error "没有权限"
 // @Failure 500 {object} errcode.Error "内部错误"
 // @Router /api/v1/tags/{id} [delete]
 func (t Tag) Delete(c *gin.Context) {
@@ -129,17 +170,58 @@ func (t Tag) Delete(c *gin.Context) {
 	response := app.NewResponse(c)
 	valid, errs := app.BindAndValid(c, &param)
 	if !valid {
-		global.Logger.Errorf("app.BindAndValid errs: %v", errs)
+		global.Logger.WithContext(c.Request.Context()).Errorf("app.BindAndValid errs: %v", errs)
 		response.ToErrorResponse(errcode.InvalidParams.WithDetails(errs.Errors()...))
 		return
 	}
 	svc := service.New(c.Request.Context())
 	err := svc.DeleteTag(&param)
+	if errors.Is(err, service.ErrForbidden) {
+		response.ToErrorResponse(errcode.Forbidden)
+		return
+	}
 	if err != nil {
-		global.Logger.Errorf("svc.DeleteTag err: %v", err)
+		global.Logger.WithContext(c.Request.Context()).Errorf("svc.DeleteTag err: %v", err)
 		response.ToErrorResponse(errcode.ErrorDeleteTagFail)
 		return
 	}
 	response.ToResponse(gin.H{})
 	return
 }
+
+// @Summary 按 slug 获取标签，旧的 slug 以 301 重定向到当前 slug
+// @Produce  json
+// @Param slug path string true "标签 slug"
+// @Param state query int false "状态" Enums(0, 1) default(1)
+// @Success 200 {object} model.Tag "成功"
+// @Success 301 {string} string "slug 已变更，Location 为新地址"
+// @Failure 400 {object} errcode.Error "请求错误"
+// @Failure 404 {object} errcode.Error "标签不存在"
+// @Failure 500 {object} errcode.Error "内部错误"
+// @Router /api/v1/tags/by-slug/{slug} [get]
+func (t Tag) GetBySlug(c *gin.Context) {
+	param := service.TagSlugRequest{Slug: c.Param("slug")}
+	response := app.NewResponse(c)
+	valid, errs := app.BindAndValid(c, &param)
+	if !valid {
+		global.Logger.WithContext(c.Request.Context()).Errorf("app.BindAndValid errs: %v", errs)
+		response.ToErrorResponse(errcode.InvalidParams.WithDetails(errs.Errors()...))
+		return
+	}
+	svc := service.New(c.Request.Context())
+	tag, redirect, err := svc.GetTagBySlug(&param)
+	if errors.Is(err, service.ErrTagNotFound) {
+		response.ToErrorResponse(errcode.NotFound)
+		return
+	}
+	if err != nil {
+		global.Logger.WithContext(c.Request.Context()).Errorf("svc.GetTagBySlug err: %v", err)
+		response.ToErrorResponse(errcode.ErrorGetTagListFail)
+		return
+	}
+	if redirect != "" {
+		redirectToSlug(c, "/api/v1/tags/by-slug/", redirect)
+		return
+	}
+	response.ToResponse(tag)
+}
@@ -0,0 +1,128 @@
+package v1
+
+import (
+	"errors"
+
+	"blog-service/global"
+	"blog-service/internal/service"
+	"blog-service/pkg/app"
+	"blog-service/pkg/convert"
+	"blog-service/pkg/errcode"
+	"github.com/gin-gonic/gin"
+)
+
+type User struct{}
+
+func NewUser() User {
+	return User{}
+}
+
+// @Summary 获取用户列表
+// @Produce  json
+// @Param role query string false "角色" Enums(author, editor, admin)
+// @Param page query int false "页码"
+// @Param page_size query int false "每页数量"
+// @Success 200 {object} model.UserSwagger "成功"
+// @Failure 400 {object} errcode.Error "请求错误"
+// @Failure 403 {object} errcode.Error "没有权限"
+// @Failure 500 {object} errcode.Error "内部错误"
+// @Router /api/v1/users [get]
+func (u User) List(c *gin.Context) {
+	param := service.UserListRequest{}
+	response := app.NewResponse(c)
+	valid, errs := app.BindAndValid(c, &param)
+	if !valid {
+		global.Logger.WithContext(c.Request.Context()).Errorf("app.BindAndValid errs: %v", errs)
+		response.ToErrorResponse(errcode.InvalidParams.WithDetails(errs.Errors()...))
+		return
+	}
+	svc := service.New(c.Request.Context())
+	pager := app.Pager{Page: app.GetPage(c), PageSize: app.GetPageSize(c)}
+	totalRows, err := svc.CountUser(&param)
+	if err != nil {
+		global.Logger.WithContext(c.Request.Context()).Errorf("svc.CountUser err: %v", err)
+		response.ToErrorResponse(errcode.ErrorCountUserFail)
+		return
+	}
+	users, err := svc.GetUserList(&param, &pager)
+	if err != nil {
+		global.Logger.WithContext(c.Request.Context()).Errorf("svc.GetUserList err: %v", err)
+		response.ToErrorResponse(errcode.ErrorGetUserListFail)
+		return
+	}
+	response.ToResponseList(users, totalRows)
+}
+
+// @Summary 新增用户
+// @Produce  json
+// @Param username body string true "用户名" minlength(2) maxlength(100)
+// @Param password body string true "密码" minlength(8) maxlength(72)
+// @Param role body string true "角色" Enums(author, editor, admin)
+// @Success 200 {object} model.User "成功"
+// @Failure 400 {object} errcode.Error "请求错误"
+// @Failure 403 {object} errcode.Error "没有权限"
+// @Failure 500 {object} errcode.Error "内部错误"
+// @Router /api/v1/users [post]
+func (u User) Create(c *gin.Context) {
+	param := service.CreateUserRequest{}
+	response := app.NewResponse(c)
+	valid, errs := app.BindAndValid(c, &param)
+	if !valid {
+		global.Logger.WithContext(c.Request.Context()).Errorf("app.BindAndValid errs: %v", errs)
+		response.ToErrorResponse(errcode.InvalidParams.WithDetails(errs.Errors()...))
+		return
+	}
+	svc := service.New(c.Request.Context())
+	user, err := svc.CreateUser(&param)
+	switch {
+	case errors.Is(err, service.ErrForbidden):
+		response.ToErrorResponse(errcode.Forbidden)
+		return
+	case errors.Is(err, service.ErrUsernameExists):
+		response.ToErrorResponse(errcode.InvalidParams.WithDetails(err.Error()))
+		return
+	case err != nil:
+		global.Logger.WithContext(c.Request.Context()).Errorf("svc.CreateUser err: %v", err)
+		response.ToErrorResponse(errcode.ErrorCreateUserFail)
+		return
+	}
+	response.ToResponse(user)
+}
+
+// @Summary 更新用户
+// @Produce  json
+// @Param id path int true "用户ID"
+// @Param password body string false "密码" minlength(8) maxlength(72)
+// @Param role body string false "角色" Enums(author, editor, admin)
+// @Param state body int false "状态 0 为禁用、1 为启用" Enums(0, 1)
+// @Success 200 {string} string "成功"
+// @Failure 400 {object} errcode.Error "请求错误"
+// @Failure 403 {object} errcode.Error "没有权限"
+// @Failure 404 {object} errcode.Error "用户不存在"
+// @Failure 500 {object} errcode.Error "内部错误"
+// @Router /api/v1/users/{id} [put]
+func (u User) Update(c *gin.Context) {
+	param := service.UpdateUserRequest{ID: convert.StrTo(c.Param("id")).MustUInt32()}
+	response := app.NewResponse(c)
+	valid, errs := app.BindAndValid(c, &param)
+	if !valid {
+		global.Logger.WithContext(c.Request.Context()).Errorf("app.BindAndValid errs: %v", errs)
+		response.ToErrorResponse(errcode.InvalidParams.WithDetails(errs.Errors()...))
+		return
+	}
+	svc := service.New(c.Request.Context())
+	err := svc.UpdateUser(&param)
+	switch {
+	case errors.Is(err, service.ErrForbidden):
+		response.ToErrorResponse(errcode.Forbidden)
+		return
+	case errors.Is(err, service.ErrUserNotFound):
+		response.ToErrorResponse(errcode.NotFound)
+		return
+	case err != nil:
+		global.Logger.WithContext(c.Request.Context()).Errorf("svc.UpdateUser err: %v", err)
+		response.ToErrorResponse(errcode.ErrorUpdateUserFail)
+		return
+	}
+	response.ToResponse(gin.H{})
+}
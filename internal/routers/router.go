@@ -1,9 +1,17 @@
 package routers
 
 import (
+	"net/http"
+
 	_ "blog-service/docs"
+	"blog-service/global"
 	"blog-service/internal/middleware"
+	"blog-service/internal/routers/api"
 	v1 "blog-service/internal/routers/api/v1"
+	"blog-service/pkg/app"
+	"blog-service/pkg/limiter"
+	"blog-service/pkg/metrics"
+	"blog-service/pkg/setting"
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
@@ -11,8 +19,20 @@ import (
 
 func NewRouter() *gin.Engine {
 	r := gin.New()
-	r.Use(gin.Logger())
+	r.Use(middleware.Tracing())
+	r.Use(middleware.AccessLog(global.LogSetting.Load().AccessLogSkipPaths))
 	r.Use(gin.Recovery())
+	r.Use(middleware.Metrics())
+	limiterSetting := global.LimiterSetting.Load()
+	rateLimiter := limiter.NewLimiter(limiterRules(limiterSetting)...)
+	// 内容未变化的配置段重新读取后指针不变，只在 Limiter 配置变化时重置令牌桶
+	setting.OnChange(func() {
+		if current := global.LimiterSetting.Load(); current != limiterSetting {
+			limiterSetting = current
+			rateLimiter.ResetBuckets(limiterRules(limiterSetting)...)
+		}
+	})
+	r.Use(middleware.RateLimiter(rateLimiter))
 	r.Use(middleware.Translations())
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 	// 健康检查
@@ -21,8 +41,11 @@ func NewRouter() *gin.Engine {
 			"message": "pong",
 		})
 	})
+	healthz := api.NewHealth()
+	r.GET("/healthz", healthz.Healthz)
+	r.GET("/readyz", healthz.Readyz)
+	r.GET("/metrics", gin.WrapH(metrics.Handler()))
 	//r.Use(Cors())
-	//r.Use(middleware.JWTAuth())
 	//r.Use(middleware.Cors())
 
 	//r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
@@ -32,23 +55,81 @@ func NewRouter() *gin.Engine {
 	//r.GET("/api/v1/test3", api.Test3)
 	//r.GET("/api/v1/test4", api.Test4)
 	//r.GET
+	r.POST("/auth", api.GetAuth)
+	upload := api.NewUpload()
+	r.POST("/upload/file", middleware.JWTAuth(), upload.UploadFile)
+	r.StaticFS("/static", http.Dir(global.AppSetting.Load().UploadSavePath))
+	// 订阅源，/tags/:id/feed.* 只包含该标签下的文章
+	feed := api.NewFeed()
+	r.GET("/feed.rss", feed.RSS)
+	r.GET("/feed.atom", feed.Atom)
+	r.GET("/feed.json", feed.JSON)
+	r.GET("/tags/:id/feed.rss", feed.RSS)
+	r.GET("/tags/:id/feed.atom", feed.Atom)
+	r.GET("/tags/:id/feed.json", feed.JSON)
+	sitemap := api.NewSitemap()
+	r.GET("/sitemap.xml", sitemap.Index)
+	r.GET("/sitemaps/:page", sitemap.Page)
 	article := v1.NewArticle()
 	tag := v1.NewTag()
+	comment := v1.NewComment()
+	user := v1.NewUser()
+	revision := v1.NewArticleRevision()
 	apiv1 := r.Group("/api/v1")
 	{
 		apiv1.GET("/test")
-		apiv1.POST("/tags", tag.Create)
-		apiv1.DELETE("/tags/:id", tag.Delete)
-		apiv1.PUT("/tags/:id", tag.Update)
-		apiv1.PATCH("/tags/:id/state", tag.Update)
 		apiv1.GET("/tags", tag.List)
+		apiv1.GET("/tags/by-slug/:slug", tag.GetBySlug)
+		// 携带 Token 时可以按 state 读取有权编辑的未发布文章
+		apiv1.GET("/articles/:id", middleware.OptionalJWTAuth(), article.Get)
+		apiv1.GET("/articles/by-slug/:slug", middleware.OptionalJWTAuth(), article.GetBySlug)
+		apiv1.GET("/articles", middleware.OptionalJWTAuth(), article.List)
+		apiv1.GET("/search", v1.NewSearch().Search)
+		apiv1.GET("/articles/:id/comments", comment.List)
+		apiv1.POST("/articles/:id/comments", comment.Create)
+	}
+	// 写操作需要携带有效的 Token，并按角色校验权限
+	apiv1Auth := apiv1.Group("")
+	apiv1Auth.Use(middleware.JWTAuth())
+	{
+		tagManage := middleware.RequirePermission(app.PermTagManage)
+		apiv1Auth.POST("/tags", tagManage, tag.Create)
+		apiv1Auth.DELETE("/tags/:id", tagManage, tag.Delete)
+		apiv1Auth.PUT("/tags/:id", tagManage, tag.Update)
+		apiv1Auth.PATCH("/tags/:id/state", tagManage, tag.Update)
+
+		articleUpdate := middleware.RequirePermission(app.PermArticleUpdateOwn, app.PermArticleUpdateAny)
+		apiv1Auth.POST("/articles", middleware.RequirePermission(app.PermArticleCreate), article.Create)
+		apiv1Auth.DELETE("/articles/:id", middleware.RequirePermission(app.PermArticleDeleteOwn, app.PermArticleDeleteAny), article.Delete)
+		apiv1Auth.PUT("/articles/:id", articleUpdate, article.Update)
+		apiv1Auth.PATCH("/articles/:id/state", articleUpdate, article.Update)
+		apiv1Auth.GET("/articles/:id/revisions", articleUpdate, revision.List)
+		apiv1Auth.GET("/articles/:id/revisions/:revision_id", articleUpdate, revision.Get)
+		apiv1Auth.GET("/articles/:id/revisions/:revision_id/diff", articleUpdate, revision.Diff)
+		apiv1Auth.POST("/articles/:id/revisions/:revision_id/restore", articleUpdate, revision.Restore)
 
-		apiv1.POST("/articles", article.Create)
-		apiv1.DELETE("/articles/:id", article.Delete)
-		apiv1.PUT("/articles/:id", article.Update)
-		apiv1.PATCH("/articles/:id/state", article.Update)
-		apiv1.GET("/articles/:id", article.Get)
-		apiv1.GET("/articles", article.List)
+		commentModerate := middleware.RequirePermission(app.PermCommentModerate)
+		apiv1Auth.GET("/comments", commentModerate, comment.ModerationList)
+		apiv1Auth.PATCH("/comments/:id/state", commentModerate, comment.UpdateState)
+
+		userManage := middleware.RequirePermission(app.PermUserManage)
+		apiv1Auth.GET("/users", userManage, user.List)
+		apiv1Auth.POST("/users", userManage, user.Create)
+		apiv1Auth.PUT("/users/:id", userManage, user.Update)
 	}
 	return r
 }
+
+func limiterRules(limiterSetting *setting.LimiterSettingS) []limiter.BucketRule {
+	var rules []limiter.BucketRule
+	for _, rule := range limiterSetting.Rules {
+		rules = append(rules, limiter.BucketRule{
+			Key:          rule.Key,
+			FillInterval: rule.FillInterval,
+			Capacity:     rule.Capacity,
+			Quantum:      rule.Quantum,
+			PerClient:    rule.PerClient,
+		})
+	}
+	return rules
+}
@@ -1,36 +1,478 @@
 package service
 
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"blog-service/internal/dao"
+	"blog-service/internal/model"
+	"blog-service/pkg/app"
+	"blog-service/pkg/markdown"
+	"gorm.io/gorm"
+)
+
 type ArticleRequest struct {
-	ID    int32 `form:"id" binding:"required,gte=1"`
-	State uint8 `form:"state,default=1" binding:"oneof=0 1"`
+	ID    uint32 `form:"id" binding:"required,gte=1"`
+	State uint8  `form:"state,default=1" binding:"oneof=0 1 2 3 4"`
 }
 
 type ArticleListRequest struct {
-	TagID int32 `form:"tag_id" binding:"gte=1"`
-	State uint8 `form:"state,default=1" binding:"oneof=0 1"`
+	TagID uint32 `form:"tag_id" binding:"omitempty,gte=1"`
+	State uint8  `form:"state,default=1" binding:"oneof=0 1 2 3 4"`
 }
 
 type CreateArticleRequest struct {
-	TagID         int32  `form:"tag_id" binding:"required,gte=1"`
-	Title         string `form:"title" binding:"required,min=2,max=100"`
-	Desc          string `form:"desc" binding:"required,min=2,max=255"`
-	Content       string `form:"content" binding:"required,min=2,max=4294967295"`
-	CoverImageUrl string `form:"cover_image_url" binding:"required,url"`
-	CreatedBy     string `form:"created_by" binding:"required,min=2,max=100"`
-	State         uint8  `form:"state,default=1" binding:"oneof=0 1"`
+	TagIDs        []uint32 `form:"tag_ids" binding:"required,min=1,dive,gte=1"`
+	Title         string   `form:"title" binding:"required,min=2,max=100"`
+	Desc          string   `form:"desc" binding:"required,min=2,max=255"`
+	Content       string   `form:"content" binding:"required,min=2,max=4294967295"`
+	CoverImageUrl string   `form:"cover_image_url" binding:"required,url"`
+	// 未传时有发布权限的用户直接发布，否则保存为草稿
+	State *uint8 `form:"state" binding:"omitempty,oneof=0 1 2 3 4"`
+	// 定时发布的时间戳，state 为定时发布时必须晚于当前时间
+	PublishAt uint32 `form:"publish_at"`
 }
 
 type UpdateArticleRequest struct {
-	ID            int32  `form:"id" binding:"required,gte=1"`
-	TagID         int32  `form:"tag_id" binding:"required,gte=1"`
-	Title         string `form:"title" binding:"min=2,max=100"`
-	Desc          string `form:"desc" binding:"min=2,max=255"`
-	Content       string `form:"content" binding:"min=2,max=4294967295"`
-	CoverImageUrl string `form:"cover_image_url" binding:"url"`
-	ModifiedBy    string `form:"modified_by" binding:"required,min=2,max=100"`
-	State         uint8  `form:"state,default=1" binding:"oneof=0 1"`
+	ID            uint32   `form:"id" binding:"required,gte=1"`
+	TagIDs        []uint32 `form:"tag_ids" binding:"omitempty,dive,gte=1"`
+	Title         string   `form:"title" binding:"omitempty,min=2,max=100"`
+	Desc          string   `form:"desc" binding:"omitempty,min=2,max=255"`
+	Content       string   `form:"content" binding:"omitempty,min=2,max=4294967295"`
+	CoverImageUrl string   `form:"cover_image_url" binding:"omitempty,url"`
+	// 未传时保持原有状态
+	State *uint8 `form:"state" binding:"omitempty,oneof=0 1 2 3 4"`
+	// 定时发布的时间戳，文章已是定时发布时传入可修改计划发布时间
+	PublishAt uint32 `form:"publish_at"`
 }
 
 type DeleteArticleRequest struct {
-	ID int32 `form:"id" binding:"required,gte=1"`
+	ID uint32 `form:"id" binding:"required,gte=1"`
+}
+
+// 返回给客户端的文章结构，附带所属标签
+type Article struct {
+	ID            uint32       `json:"id"`
+	Title         string       `json:"title"`
+	Slug          string       `json:"slug"`
+	Desc          string       `json:"desc"`
+	Content       string       `json:"content"`
+	CoverImageUrl string       `json:"cover_image_url"`
+	State         uint8        `json:"state"`
+	PublishAt     uint32       `json:"publish_at"`
+	Tags          []*model.Tag `json:"tags"`
+}
+
+// 单篇文章附带渲染后的 HTML 和目录
+type ArticleDetail struct {
+	*Article
+	ContentHTML string              `json:"content_html"`
+	TOC         []*markdown.Heading `json:"toc"`
+}
+
+func (svc *Service) GetArticle(param *ArticleRequest) (*ArticleDetail, error) {
+	state, createdBy := svc.articleReadScope(param.State)
+	article, err := svc.dao.GetArticle(param.ID, state)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && !articleVisible(&article, state, createdBy)) {
+		return nil, ErrArticleNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return svc.newArticleDetail(&article)
+}
+
+// 只有已发布的文章对读者可见，查询其他状态需要编辑文章的权限，否则按已发布查询
+// 只能编辑自己文章的用户只能看到自己创建的，此时返回需要限定的创建人
+func (svc *Service) articleReadScope(state uint8) (uint8, string) {
+	if state == model.ARTICLE_STATE_PUBLISHED {
+		return state, ""
+	}
+	principal := app.PrincipalFromContext(svc.ctx)
+	switch {
+	case principal.Can(app.PermArticleUpdateAny):
+		return state, ""
+	case principal.Can(app.PermArticleUpdateOwn):
+		return state, principal.Name
+	}
+	return model.ARTICLE_STATE_PUBLISHED, ""
+}
+
+func articleVisible(article *model.Article, state uint8, createdBy string) bool {
+	return article.State == state && (createdBy == "" || article.CreatedBy == createdBy)
+}
+
+func (svc *Service) newArticleDetail(article *model.Article) (*ArticleDetail, error) {
+	tags, err := svc.getArticleTags([]uint32{article.ID})
+	if err != nil {
+		return nil, err
+	}
+	// 尚未渲染过的旧文章临时渲染，可通过 render-content 子命令写入
+	if article.ContentHTML == "" && article.Content != "" {
+		article.ContentHTML, article.ContentTOC, err = renderArticleContent(article.Content)
+		if err != nil {
+			return nil, err
+		}
+	}
+	detail := &ArticleDetail{Article: newArticle(article, tags[article.ID]), ContentHTML: article.ContentHTML, TOC: []*markdown.Heading{}}
+	if article.ContentTOC != "" {
+		if err := json.Unmarshal([]byte(article.ContentTOC), &detail.TOC); err != nil {
+			return nil, err
+		}
+	}
+	return detail, nil
+}
+
+func (svc *Service) GetArticleList(param *ArticleListRequest, pager *app.Pager) ([]*Article, int64, error) {
+	state, createdBy := svc.articleReadScope(param.State)
+	articleCount, err := svc.dao.CountArticleList(param.TagID, state, createdBy)
+	if err != nil {
+		return nil, 0, err
+	}
+	articles, err := svc.dao.GetArticleList(param.TagID, state, createdBy, pager.Page, pager.PageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+	articleList, err := svc.newArticleList(articles)
+	if err != nil {
+		return nil, 0, err
+	}
+	return articleList, articleCount, nil
+}
+
+// 游标分页不统计总数，避免大表上的 COUNT 查询
+func (svc *Service) GetArticleCursorList(param *ArticleListRequest, pager *app.CursorPager) ([]*Article, error) {
+	state, createdBy := svc.articleReadScope(param.State)
+	articles, err := svc.dao.GetArticleListByCursor(param.TagID, state, createdBy, pager.Cursor, pager.Limit)
+	if err != nil {
+		return nil, err
+	}
+	return svc.newArticleList(app.CursorPage(pager, articles))
+}
+
+// 新文章视为从草稿变更到请求的状态，没有发布权限的用户只能保存为草稿或提交审核
+func (svc *Service) CreateArticle(param *CreateArticleRequest) error {
+	principal := app.PrincipalFromContext(svc.ctx)
+	if !principal.Can(app.PermArticleCreate) {
+		return ErrForbidden
+	}
+	var state uint8 = model.ARTICLE_STATE_DRAFT
+	if principal.Can(app.PermArticlePublish) {
+		state = model.ARTICLE_STATE_PUBLISHED
+	}
+	if param.State != nil {
+		state = *param.State
+	}
+	if state != model.ARTICLE_STATE_DRAFT {
+		if err := checkArticleTransition(principal, model.ARTICLE_STATE_DRAFT, state); err != nil {
+			return err
+		}
+	}
+	publishAt, err := articlePublishAt(state, 0, param.PublishAt, uint32(time.Now().Unix()))
+	if err != nil {
+		return err
+	}
+	contentHTML, contentTOC, err := renderArticleContent(param.Content)
+	if err != nil {
+		return err
+	}
+	defer invalidateSitemap()
+	var articleID uint32
+	err = svc.dao.Transaction(func(tx *dao.Dao) error {
+		slug, err := uniqueSlug(tx, model.SLUG_KIND_ARTICLE, param.Title, 0)
+		if err != nil {
+			return err
+		}
+		article, err := tx.CreateArticle(&dao.Article{
+			Title:         param.Title,
+			Slug:          slug,
+			Desc:          param.Desc,
+			Content:       param.Content,
+			ContentHTML:   contentHTML,
+			ContentTOC:    contentTOC,
+			CoverImageUrl: param.CoverImageUrl,
+			State:         state,
+			PublishAt:     publishAt,
+			CreatedBy:     principal.Name,
+		})
+		if err != nil {
+			return err
+		}
+		articleID = article.ID
+		if err := replaceArticleTags(tx, article.ID, param.TagIDs, principal.Name); err != nil {
+			return err
+		}
+		return recordArticleRevision(tx, article.ID, principal.Name)
+	})
+	if err != nil {
+		return err
+	}
+	if state != model.ARTICLE_STATE_DRAFT {
+		svc.logArticleTransition(articleID, model.ARTICLE_STATE_DRAFT, state, principal.Name)
+	}
+	svc.syncSearchIndex(articleID)
+	return nil
+}
+
+// 作者只能编辑自己的文章，状态变更按 articleTransitions 校验
+func (svc *Service) UpdateArticle(param *UpdateArticleRequest) error {
+	principal := app.PrincipalFromContext(svc.ctx)
+	article, err := svc.checkArticleOwner(param.ID, app.PermArticleUpdateOwn, app.PermArticleUpdateAny)
+	if err != nil {
+		return err
+	}
+	state := article.State
+	if param.State != nil {
+		state = *param.State
+	}
+	// 定时发布的文章传入新的 publish_at 时重新安排发布时间
+	reschedule := state == model.ARTICLE_STATE_SCHEDULED && article.State == model.ARTICLE_STATE_SCHEDULED &&
+		param.PublishAt != 0 && param.PublishAt != article.PublishAt
+	stateChanged := state != article.State || reschedule
+	publishAt := article.PublishAt
+	if stateChanged {
+		if reschedule && !principal.Can(app.PermArticlePublish) {
+			return ErrForbidden
+		}
+		if !reschedule {
+			if err := checkArticleTransition(principal, article.State, state); err != nil {
+				return err
+			}
+		}
+		publishAt, err = articlePublishAt(state, article.PublishAt, param.PublishAt, uint32(time.Now().Unix()))
+		if err != nil {
+			return err
+		}
+	}
+	var contentHTML, contentTOC string
+	if param.Content != "" {
+		contentHTML, contentTOC, err = renderArticleContent(param.Content)
+		if err != nil {
+			return err
+		}
+	}
+	defer invalidateSitemap()
+	err = svc.dao.Transaction(func(tx *dao.Dao) error {
+		// 标题修改后重新生成 slug，旧 slug 重定向到新 slug
+		var slug string
+		if param.Title != "" && param.Title != article.Title {
+			var err error
+			slug, err = changeSlug(tx, model.SLUG_KIND_ARTICLE, article.Slug, param.Title, param.ID, principal.Name)
+			if err != nil {
+				return err
+			}
+		}
+		err := tx.UpdateArticle(&dao.Article{
+			ID:            param.ID,
+			Title:         param.Title,
+			Slug:          slug,
+			Desc:          param.Desc,
+			Content:       param.Content,
+			ContentHTML:   contentHTML,
+			ContentTOC:    contentTOC,
+			CoverImageUrl: param.CoverImageUrl,
+			ModifiedBy:    principal.Name,
+		})
+		if err != nil {
+			return err
+		}
+		if stateChanged {
+			ok, err := tx.TransitionArticle(param.ID, article.State, state, publishAt, principal.Name)
+			if err != nil {
+				return err
+			}
+			// 读取文章后状态已被其他请求或定时发布修改
+			if !ok {
+				return ErrInvalidTransition
+			}
+		}
+		// 未传 tag_ids 时保留原有标签
+		if param.TagIDs != nil {
+			if err := replaceArticleTags(tx, param.ID, param.TagIDs, principal.Name); err != nil {
+				return err
+			}
+		}
+		// 每次更新都保存修订，修订中包含状态、封面和标签
+		return recordArticleRevision(tx, param.ID, principal.Name)
+	})
+	if err != nil {
+		return err
+	}
+	if state != article.State {
+		svc.logArticleTransition(param.ID, article.State, state, principal.Name)
+	}
+	svc.syncSearchIndex(param.ID)
+	return nil
+}
+
+func (svc *Service) DeleteArticle(param *DeleteArticleRequest) error {
+	_, err := svc.checkArticleOwner(param.ID, app.PermArticleDeleteOwn, app.PermArticleDeleteAny)
+	if err != nil {
+		return err
+	}
+	defer invalidateSitemap()
+	err = svc.dao.Transaction(func(tx *dao.Dao) error {
+		err := tx.DeleteArticle(param.ID)
+		if err != nil {
+			return err
+		}
+		err = tx.DeleteArticleComments(param.ID)
+		if err != nil {
+			return err
+		}
+		return tx.DeleteArticleTags(param.ID)
+	})
+	if err != nil {
+		return err
+	}
+	svc.syncSearchIndex(param.ID)
+	return nil
+}
+
+// 渲染 Markdown 正文，目录序列化为 JSON 与正文一起保存
+func renderArticleContent(content string) (string, string, error) {
+	result, err := markdown.Render(content)
+	if err != nil {
+		return "", "", err
+	}
+	if result.TOC == nil {
+		result.TOC = []*markdown.Heading{}
+	}
+	toc, err := json.Marshal(result.TOC)
+	if err != nil {
+		return "", "", err
+	}
+	return result.HTML, string(toc), nil
+}
+
+// 重新渲染全部文章的正文，返回处理的文章数，用于渲染规则变化后或填充旧文章
+func (svc *Service) RenderAllArticleContent() (int, error) {
+	count := 0
+	var afterID uint32
+	for {
+		articles, err := svc.dao.GetArticlesAfterID(afterID, rebuildBatchSize)
+		if err != nil {
+			return count, err
+		}
+		if len(articles) == 0 {
+			return count, nil
+		}
+		for _, article := range articles {
+			contentHTML, contentTOC, err := renderArticleContent(article.Content)
+			if err != nil {
+				return count, fmt.Errorf("render article %d: %w", article.ID, err)
+			}
+			if err := svc.dao.UpdateArticleContentHTML(article.ID, contentHTML, contentTOC); err != nil {
+				return count, err
+			}
+			count++
+		}
+		afterID = articles[len(articles)-1].ID
+	}
+}
+
+// 拥有 anyPerm 可以操作任意文章，只拥有 ownPerm 时只能操作自己创建的文章
+func (svc *Service) checkArticleOwner(articleID uint32, ownPerm, anyPerm app.Permission) (*model.Article, error) {
+	principal := app.PrincipalFromContext(svc.ctx)
+	if !principal.Can(ownPerm, anyPerm) {
+		return nil, ErrForbidden
+	}
+	article, err := svc.dao.GetArticleByID(articleID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrArticleNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !principal.Can(anyPerm) && article.CreatedBy != principal.Name {
+		return nil, ErrForbidden
+	}
+	return &article, nil
+}
+
+// 按文章 ID 分组返回关联的标签
+func (svc *Service) getArticleTags(articleIDs []uint32) (map[uint32][]*model.Tag, error) {
+	rows, err := svc.dao.GetArticleTagsByAIDs(articleIDs)
+	if err != nil {
+		return nil, err
+	}
+	tags := make(map[uint32][]*model.Tag, len(articleIDs))
+	for _, row := range rows {
+		tags[row.ArticleID] = append(tags[row.ArticleID], &model.Tag{
+			Model: &model.Model{
+				ID:         row.TagID,
+				CreatedBy:  row.TagCreatedBy,
+				ModifiedBy: row.TagModifiedBy,
+				CreatedOn:  row.TagCreatedOn,
+				ModifiedOn: row.TagModifiedOn,
+			},
+			Name:  row.TagName,
+			Slug:  row.TagSlug,
+			State: row.TagState,
+		})
+	}
+	return tags, nil
+}
+
+// 批量查询标签，组装成文章列表
+func (svc *Service) newArticleList(articles []*model.Article) ([]*Article, error) {
+	articleIDs := make([]uint32, 0, len(articles))
+	for _, article := range articles {
+		articleIDs = append(articleIDs, article.ID)
+	}
+	tags, err := svc.getArticleTags(articleIDs)
+	if err != nil {
+		return nil, err
+	}
+	articleList := make([]*Article, 0, len(articles))
+	for _, article := range articles {
+		articleList = append(articleList, newArticle(article, tags[article.ID]))
+	}
+	return articleList, nil
+}
+
+func newArticle(article *model.Article, tags []*model.Tag) *Article {
+	if tags == nil {
+		tags = []*model.Tag{}
+	}
+	return &Article{
+		ID:            article.ID,
+		Title:         article.Title,
+		Slug:          article.Slug,
+		Desc:          article.Desc,
+		Content:       article.Content,
+		CoverImageUrl: article.CoverImageUrl,
+		State:         article.State,
+		PublishAt:     article.PublishAt,
+		Tags:          tags,
+	}
+}
+
+var ErrInvalidTags = errors.New("tag_ids contains tags that do not exist or are disabled")
+
+// 校验标签都存在且已启用后替换文章的标签，需要在写入文章的同一个事务中调用
+func replaceArticleTags(tx *dao.Dao, articleID uint32, tagIDs []uint32, operator string) error {
+	tagIDs = uniqueTagIDs(tagIDs)
+	count, err := tx.CountOpenTags(tagIDs)
+	if err != nil {
+		return err
+	}
+	if count != int64(len(tagIDs)) {
+		return ErrInvalidTags
+	}
+	return tx.ReplaceArticleTags(articleID, tagIDs, operator)
+}
+
+func uniqueTagIDs(tagIDs []uint32) []uint32 {
+	seen := make(map[uint32]bool, len(tagIDs))
+	result := make([]uint32, 0, len(tagIDs))
+	for _, id := range tagIDs {
+		if !seen[id] {
+			seen[id] = true
+			result = append(result, id)
+		}
+	}
+	return result
 }
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"blog-service/global"
+	"blog-service/internal/dao"
+	"blog-service/internal/model"
+	"blog-service/pkg/app"
+)
+
+// 定时发布每批处理的文章数
+const publishBatchSize = 100
+
+// 定时发布时记录的修改人
+const schedulerName = "scheduler"
+
+var (
+	ErrInvalidTransition = errors.New("article state transition is not allowed")
+	ErrInvalidPublishAt  = errors.New("publish_at must be in the future for scheduled articles")
+)
+
+// 允许的状态变更，定时发布到期后由调度器改为已发布
+var articleTransitions = map[uint8][]uint8{
+	model.ARTICLE_STATE_DRAFT: {
+		model.ARTICLE_STATE_IN_REVIEW, model.ARTICLE_STATE_SCHEDULED, model.ARTICLE_STATE_PUBLISHED, model.ARTICLE_STATE_ARCHIVED,
+	},
+	model.ARTICLE_STATE_IN_REVIEW: {
+		model.ARTICLE_STATE_DRAFT, model.ARTICLE_STATE_SCHEDULED, model.ARTICLE_STATE_PUBLISHED,
+	},
+	model.ARTICLE_STATE_SCHEDULED: {
+		model.ARTICLE_STATE_DRAFT, model.ARTICLE_STATE_PUBLISHED,
+	},
+	model.ARTICLE_STATE_PUBLISHED: {
+		model.ARTICLE_STATE_DRAFT, model.ARTICLE_STATE_ARCHIVED,
+	},
+	model.ARTICLE_STATE_ARCHIVED: {
+		model.ARTICLE_STATE_DRAFT, model.ARTICLE_STATE_PUBLISHED,
+	},
+}
+
+// 作者可以提交审核和撤回审核，其余状态变更需要发布权限
+func checkArticleTransition(p *app.Principal, from, to uint8) error {
+	allowed := false
+	for _, state := range articleTransitions[from] {
+		if state == to {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return ErrInvalidTransition
+	}
+	if (from == model.ARTICLE_STATE_DRAFT && to == model.ARTICLE_STATE_IN_REVIEW) ||
+		(from == model.ARTICLE_STATE_IN_REVIEW && to == model.ARTICLE_STATE_DRAFT) {
+		return nil
+	}
+	if !p.Can(app.PermArticlePublish) {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// 计算进入 to 状态后的发布时间，requested 为请求中的 publish_at，只在定时发布时使用
+// 定时发布要求发布时间晚于当前时间；发布时保留已有的发布时间，未发布过或提前发布定时文章时取当前时间
+func articlePublishAt(to uint8, current, requested, now uint32) (uint32, error) {
+	switch to {
+	case model.ARTICLE_STATE_SCHEDULED:
+		publishAt := requested
+		if publishAt == 0 {
+			publishAt = current
+		}
+		if publishAt <= now {
+			return 0, ErrInvalidPublishAt
+		}
+		return publishAt, nil
+	case model.ARTICLE_STATE_PUBLISHED:
+		if current > 0 && current <= now {
+			return current, nil
+		}
+		return now, nil
+	}
+	return current, nil
+}
+
+func (svc *Service) logArticleTransition(articleID uint32, from, to uint8, by string) {
+	global.Logger.WithContext(svc.ctx).Infof("article %d state %s -> %s by %s",
+		articleID, model.ArticleStateName(from), model.ArticleStateName(to), by)
+}
+
+// 发布所有到期的定时发布文章，返回发布的文章数，由服务进程中的调度器定期调用
+func (svc *Service) PublishDueArticles() (int, error) {
+	published := 0
+	defer func() {
+		if published > 0 {
+			invalidateSitemap()
+		}
+	}()
+	for {
+		articles, err := svc.dao.GetDueArticles(uint32(time.Now().Unix()), publishBatchSize)
+		if err != nil {
+			return published, err
+		}
+		for _, article := range articles {
+			// 状态变更与修订记录在同一个事务中写入，修订历史中包含定时发布
+			var ok bool
+			err := svc.dao.Transaction(func(tx *dao.Dao) error {
+				var err error
+				ok, err = tx.TransitionArticle(article.ID, model.ARTICLE_STATE_SCHEDULED, model.ARTICLE_STATE_PUBLISHED,
+					article.PublishAt, schedulerName)
+				if err != nil || !ok {
+					return err
+				}
+				return recordArticleRevision(tx, article.ID, schedulerName)
+			})
+			if err != nil {
+				return published, err
+			}
+			// 查询后已被编辑改为其他状态
+			if !ok {
+				continue
+			}
+			svc.logArticleTransition(article.ID, model.ARTICLE_STATE_SCHEDULED, model.ARTICLE_STATE_PUBLISHED, schedulerName)
+			svc.syncSearchIndex(article.ID)
+			published++
+		}
+		if len(articles) < publishBatchSize {
+			return published, nil
+		}
+	}
+}
@@ -0,0 +1,211 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"blog-service/internal/dao"
+	"blog-service/internal/model"
+	"blog-service/pkg/app"
+	"github.com/pmezard/go-difflib/difflib"
+	"gorm.io/gorm"
+)
+
+var ErrRevisionNotFound = errors.New("revision not found")
+
+// 对比修订时每处改动前后保留的上下文行数
+const diffContextLines = 3
+
+type ArticleRevisionListRequest struct {
+	ArticleID uint32 `form:"article_id" binding:"required,gte=1"`
+}
+
+type ArticleRevisionRequest struct {
+	ArticleID uint32 `form:"article_id" binding:"required,gte=1"`
+	ID        uint32 `form:"revision_id" binding:"required,gte=1"`
+}
+
+// Base 未传时与上一个修订比较
+type DiffArticleRevisionRequest struct {
+	ArticleID uint32 `form:"article_id" binding:"required,gte=1"`
+	ID        uint32 `form:"revision_id" binding:"required,gte=1"`
+	Base      uint32 `form:"base" binding:"omitempty,gte=1"`
+}
+
+// 按字段给出 unified 格式的行级差异，字段未改变时为空
+type ArticleRevisionDiff struct {
+	From          uint32 `json:"from"`
+	To            uint32 `json:"to"`
+	Title         string `json:"title"`
+	Desc          string `json:"desc"`
+	Content       string `json:"content"`
+	CoverImageUrl string `json:"cover_image_url"`
+	State         string `json:"state"`
+	TagIDs        string `json:"tag_ids"`
+}
+
+// 修订包含未发布的内容，只有可以编辑该文章的用户能查看
+func (svc *Service) CountArticleRevision(param *ArticleRevisionListRequest) (int64, error) {
+	if _, err := svc.checkArticleOwner(param.ArticleID, app.PermArticleUpdateOwn, app.PermArticleUpdateAny); err != nil {
+		return 0, err
+	}
+	return svc.dao.CountArticleRevision(param.ArticleID)
+}
+
+func (svc *Service) GetArticleRevisionList(param *ArticleRevisionListRequest, pager *app.Pager) ([]*model.ArticleRevision, error) {
+	if _, err := svc.checkArticleOwner(param.ArticleID, app.PermArticleUpdateOwn, app.PermArticleUpdateAny); err != nil {
+		return nil, err
+	}
+	return svc.dao.GetArticleRevisionList(param.ArticleID, pager.Page, pager.PageSize)
+}
+
+func (svc *Service) GetArticleRevision(param *ArticleRevisionRequest) (*model.ArticleRevision, error) {
+	if _, err := svc.checkArticleOwner(param.ArticleID, app.PermArticleUpdateOwn, app.PermArticleUpdateAny); err != nil {
+		return nil, err
+	}
+	return svc.getArticleRevision(param.ArticleID, param.ID)
+}
+
+// 比较 Base 和 ID 两个修订，ID 为第一个修订且未传 Base 时与空文章比较
+func (svc *Service) DiffArticleRevision(param *DiffArticleRevisionRequest) (*ArticleRevisionDiff, error) {
+	if _, err := svc.checkArticleOwner(param.ArticleID, app.PermArticleUpdateOwn, app.PermArticleUpdateAny); err != nil {
+		return nil, err
+	}
+	to, err := svc.getArticleRevision(param.ArticleID, param.ID)
+	if err != nil {
+		return nil, err
+	}
+	from := &model.ArticleRevision{Model: &model.Model{}}
+	if param.Base > 0 {
+		from, err = svc.getArticleRevision(param.ArticleID, param.Base)
+	} else {
+		var previous model.ArticleRevision
+		previous, err = svc.dao.GetPreviousArticleRevision(param.ArticleID, param.ID)
+		if err == nil {
+			from = &previous
+		} else if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = nil
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &ArticleRevisionDiff{From: from.ID, To: to.ID}
+	fields := []struct {
+		name     string
+		from, to string
+		out      *string
+	}{
+		{"title", from.Title, to.Title, &diff.Title},
+		{"desc", from.Desc, to.Desc, &diff.Desc},
+		{"content", from.Content, to.Content, &diff.Content},
+		{"cover_image_url", from.CoverImageUrl, to.CoverImageUrl, &diff.CoverImageUrl},
+		{"state", revisionStateName(from.State), revisionStateName(to.State), &diff.State},
+		{"tag_ids", from.TagIDs, to.TagIDs, &diff.TagIDs},
+	}
+	for _, f := range fields {
+		*f.out, err = difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        difflib.SplitLines(f.from),
+			B:        difflib.SplitLines(f.to),
+			FromFile: fmt.Sprintf("%s@%d", f.name, from.ID),
+			ToFile:   fmt.Sprintf("%s@%d", f.name, to.ID),
+			Context:  diffContextLines,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return diff, nil
+}
+
+// 以修订的标题、简述、正文、封面和标签更新文章，恢复本身也会产生一个新的修订
+// 文章状态不变，状态只能按发布流程变更；升级前的修订没有记录封面和标签，恢复时保持不变
+func (svc *Service) RestoreArticleRevision(param *ArticleRevisionRequest) error {
+	principal := app.PrincipalFromContext(svc.ctx)
+	article, err := svc.checkArticleOwner(param.ArticleID, app.PermArticleUpdateOwn, app.PermArticleUpdateAny)
+	if err != nil {
+		return err
+	}
+	revision, err := svc.getArticleRevision(param.ArticleID, param.ID)
+	if err != nil {
+		return err
+	}
+	contentHTML, contentTOC, err := renderArticleContent(revision.Content)
+	if err != nil {
+		return err
+	}
+	defer invalidateSitemap()
+	err = svc.dao.Transaction(func(tx *dao.Dao) error {
+		var slug string
+		if revision.Title != article.Title {
+			var err error
+			slug, err = changeSlug(tx, model.SLUG_KIND_ARTICLE, article.Slug, revision.Title, param.ArticleID, principal.Name)
+			if err != nil {
+				return err
+			}
+		}
+		err := tx.RestoreArticle(&dao.Article{
+			ID:            param.ArticleID,
+			Title:         revision.Title,
+			Slug:          slug,
+			Desc:          revision.Desc,
+			Content:       revision.Content,
+			ContentHTML:   contentHTML,
+			ContentTOC:    contentTOC,
+			CoverImageUrl: revision.CoverImageUrl,
+			ModifiedBy:    principal.Name,
+		})
+		if err != nil {
+			return err
+		}
+		if revision.TagIDs != "" {
+			if err := tx.ReplaceArticleTags(param.ArticleID, model.SplitTagIDs(revision.TagIDs), principal.Name); err != nil {
+				return err
+			}
+		}
+		return recordArticleRevision(tx, param.ArticleID, principal.Name)
+	})
+	if err != nil {
+		return err
+	}
+	svc.syncSearchIndex(param.ArticleID)
+	return nil
+}
+
+// 未记录状态的修订按空值比较
+func revisionStateName(state *uint8) string {
+	if state == nil {
+		return ""
+	}
+	return model.ArticleStateName(*state)
+}
+
+func (svc *Service) getArticleRevision(articleID, id uint32) (*model.ArticleRevision, error) {
+	revision, err := svc.dao.GetArticleRevision(articleID, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrRevisionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
+
+// 读取文章写入后的内容和标签保存为新的修订，需要在写入文章和标签的同一个事务中调用
+func recordArticleRevision(tx *dao.Dao, articleID uint32, createdBy string) error {
+	article, err := tx.GetArticleByID(articleID)
+	if err != nil {
+		return err
+	}
+	rows, err := tx.GetArticleTagsByAIDs([]uint32{articleID})
+	if err != nil {
+		return err
+	}
+	tagIDs := make([]uint32, 0, len(rows))
+	for _, row := range rows {
+		tagIDs = append(tagIDs, row.TagID)
+	}
+	_, err = tx.CreateArticleRevision(&article, tagIDs, createdBy)
+	return err
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"errors"
+
+	"blog-service/internal/dao"
+	"blog-service/internal/model"
+	"blog-service/pkg/app"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrArticleNotFound = errors.New("article not found")
+	ErrCommentNotFound = errors.New("comment not found")
+	ErrInvalidParent   = errors.New("parent comment does not belong to the article or is not approved")
+)
+
+type CommentListRequest struct {
+	ArticleID uint32 `form:"article_id" binding:"required,gte=1"`
+}
+
+// 审核列表，默认列出待审核的评论
+type ModerationCommentListRequest struct {
+	ArticleID uint32 `form:"article_id" binding:"omitempty,gte=1"`
+	State     uint8  `form:"state,default=0" binding:"oneof=0 1 2 3"`
+}
+
+type CreateCommentRequest struct {
+	ArticleID uint32 `form:"article_id" binding:"required,gte=1"`
+	ParentID  uint32 `form:"parent_id" binding:"omitempty,gte=1"`
+	Author    string `form:"author" binding:"required,min=2,max=100"`
+	Email     string `form:"email" binding:"omitempty,email,max=255"`
+	Content   string `form:"content" binding:"required,min=1,max=2000"`
+}
+
+type UpdateCommentStateRequest struct {
+	ID    uint32 `form:"id" binding:"required,gte=1"`
+	State *uint8 `form:"state" binding:"required,oneof=0 1 2 3"`
+}
+
+// 读者只能看到已发布文章的评论
+func (svc *Service) CountComment(param *CommentListRequest) (int64, error) {
+	if err := svc.checkPublishedArticle(param.ArticleID); err != nil {
+		return 0, err
+	}
+	return svc.dao.CountComment(param.ArticleID, model.COMMENT_STATE_APPROVED)
+}
+
+func (svc *Service) CountModerationComment(param *ModerationCommentListRequest) (int64, error) {
+	return svc.dao.CountComment(param.ArticleID, param.State)
+}
+
+// 读者只能看到已发布文章下已通过审核的评论
+func (svc *Service) GetCommentList(param *CommentListRequest, pager *app.Pager) ([]*model.Comment, error) {
+	if err := svc.checkPublishedArticle(param.ArticleID); err != nil {
+		return nil, err
+	}
+	return svc.dao.GetCommentList(param.ArticleID, model.COMMENT_STATE_APPROVED, pager.Page, pager.PageSize)
+}
+
+func (svc *Service) GetModerationCommentList(param *ModerationCommentListRequest, pager *app.Pager) ([]*model.Comment, error) {
+	return svc.dao.GetCommentList(param.ArticleID, param.State, pager.Page, pager.PageSize)
+}
+
+// 读者提交的评论需要审核后才会展示，回复的评论必须属于同一篇文章且已通过审核
+func (svc *Service) CreateComment(param *CreateCommentRequest) (*model.Comment, error) {
+	if err := svc.checkPublishedArticle(param.ArticleID); err != nil {
+		return nil, err
+	}
+	if param.ParentID > 0 {
+		parent, err := svc.dao.GetComment(param.ParentID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidParent
+		}
+		if err != nil {
+			return nil, err
+		}
+		if parent.ArticleID != param.ArticleID || parent.State != model.COMMENT_STATE_APPROVED {
+			return nil, ErrInvalidParent
+		}
+	}
+	return svc.dao.CreateComment(&dao.Comment{
+		ArticleID: param.ArticleID,
+		ParentID:  param.ParentID,
+		Author:    param.Author,
+		Email:     param.Email,
+		Content:   param.Content,
+		State:     model.COMMENT_STATE_PENDING,
+	})
+}
+
+func (svc *Service) UpdateCommentState(param *UpdateCommentStateRequest) error {
+	principal := app.PrincipalFromContext(svc.ctx)
+	if !principal.Can(app.PermCommentModerate) {
+		return ErrForbidden
+	}
+	_, err := svc.dao.GetComment(param.ID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrCommentNotFound
+	}
+	if err != nil {
+		return err
+	}
+	return svc.dao.UpdateCommentState(param.ID, *param.State, principal.Name)
+}
+
+// 文章不存在或未发布时返回 ErrArticleNotFound
+func (svc *Service) checkPublishedArticle(articleID uint32) error {
+	_, err := svc.dao.GetArticle(articleID, model.ARTICLE_STATE_PUBLISHED)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrArticleNotFound
+	}
+	return err
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"crypto/sha1"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"blog-service/global"
+	"blog-service/internal/model"
+	"github.com/gorilla/feeds"
+)
+
+var ErrTagNotFound = errors.New("tag not found")
+
+type FeedRequest struct {
+	TagID uint32 `form:"id" binding:"omitempty,gte=1"`
+}
+
+// 订阅源及其缓存校验信息，LastModified 为零值表示没有已发布的文章
+type Feed struct {
+	*feeds.Feed
+	LastModified time.Time
+	ETag         string
+}
+
+// 根据已发布的文章生成订阅源，TagID 不为 0 时只包含该标签下的文章
+func (svc *Service) GetFeed(param *FeedRequest) (*Feed, error) {
+	site := global.SiteSetting.Load()
+	title, link := site.Title, site.URL
+	if param.TagID > 0 {
+		tag, err := svc.dao.GetTag(param.TagID, model.STATE_OPEN)
+		if err != nil {
+			return nil, err
+		}
+		if tag.Model == nil {
+			return nil, ErrTagNotFound
+		}
+		title = site.Title + " - " + tag.Name
+	}
+	stat, err := svc.dao.GetArticleStat(param.TagID, model.ARTICLE_STATE_PUBLISHED)
+	if err != nil {
+		return nil, err
+	}
+	articles, err := svc.dao.GetLatestArticles(param.TagID, model.ARTICLE_STATE_PUBLISHED, site.FeedSize)
+	if err != nil {
+		return nil, err
+	}
+
+	feed := &Feed{
+		Feed: &feeds.Feed{
+			Id:          link,
+			Title:       title,
+			Link:        &feeds.Link{Href: link},
+			Description: site.Description,
+			Author:      &feeds.Author{Name: site.Author},
+		},
+		// 文章数量变化（如删除、下线）不一定会改变最近修改时间，一并计入 ETag
+		ETag: fmt.Sprintf(`W/"%x"`, sha1.Sum([]byte(fmt.Sprintf("%d|%d|%d|%s|%s|%s|%d",
+			param.TagID, stat.LastModified, stat.Total, site.Title, site.URL, site.Author, site.FeedSize)))),
+	}
+	if stat.LastModified > 0 {
+		feed.LastModified = time.Unix(int64(stat.LastModified), 0)
+		feed.Updated = feed.LastModified
+	}
+	for _, article := range articles {
+		// 条目 ID 使用不随标题变化的 ID 地址，链接使用 slug 地址
+		articleID := site.URL + "/articles/" + strconv.FormatUint(uint64(article.ID), 10)
+		articleLink := slugURL(site.URL+"/articles/", article.ID, article.Slug)
+		// 订阅源输出渲染后的 HTML，尚未渲染的旧文章使用原文
+		content := article.ContentHTML
+		if content == "" {
+			content = article.Content
+		}
+		feed.Items = append(feed.Items, &feeds.Item{
+			Id:          articleID,
+			Title:       article.Title,
+			Link:        &feeds.Link{Href: articleLink},
+			Description: article.Desc,
+			Content:     content,
+			Author:      &feeds.Author{Name: article.CreatedBy},
+			Created:     time.Unix(int64(article.PublishedOn()), 0),
+			Updated:     time.Unix(int64(article.ModifiedOn), 0),
+		})
+	}
+	return feed, nil
+}
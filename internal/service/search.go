@@ -0,0 +1,181 @@
+package service
+
+import (
+	"errors"
+
+	"blog-service/global"
+	"blog-service/internal/model"
+	"blog-service/pkg/app"
+	"blog-service/pkg/search"
+)
+
+// 重建索引时每批读取的文章数
+const rebuildBatchSize = 500
+
+var ErrSearchUnavailable = errors.New("search index is not open")
+
+type SearchRequest struct {
+	Q   string `form:"q" binding:"required,min=1,max=200"`
+	Tag string `form:"tag" binding:"max=100"`
+}
+
+// 搜索结果中的文章，附带相关度和高亮片段
+type SearchHit struct {
+	*Article
+	Score      float64             `json:"score"`
+	Highlights map[string][]string `json:"highlights"`
+}
+
+type SearchResult struct {
+	List   []*SearchHit    `json:"list"`
+	Facets []*search.Facet `json:"facets"`
+	Pager  app.Pager       `json:"pager"`
+}
+
+// 在全文索引中搜索已发布的文章，按相关度排序
+func (svc *Service) Search(param *SearchRequest, pager *app.Pager) (*SearchResult, error) {
+	if global.SearchIndex == nil {
+		return nil, ErrSearchUnavailable
+	}
+	res, err := global.SearchIndex.Search(&search.Request{
+		Query: param.Q,
+		Tag:   param.Tag,
+		From:  app.GetPageOffset(pager.Page, pager.PageSize),
+		Size:  pager.PageSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]uint32, 0, len(res.Hits))
+	for _, hit := range res.Hits {
+		ids = append(ids, hit.ID)
+	}
+	articles, err := svc.dao.GetArticlesByIDs(ids, model.ARTICLE_STATE_PUBLISHED)
+	if err != nil {
+		return nil, err
+	}
+	articleList, err := svc.newArticleList(articles)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uint32]*Article, len(articleList))
+	for _, article := range articleList {
+		byID[article.ID] = article
+	}
+
+	result := &SearchResult{
+		List:   make([]*SearchHit, 0, len(res.Hits)),
+		Facets: res.Facets,
+		Pager:  app.Pager{Page: pager.Page, PageSize: pager.PageSize, TotalRows: int64(res.Total)},
+	}
+	if result.Facets == nil {
+		result.Facets = []*search.Facet{}
+	}
+	// 保持索引返回的相关度顺序，索引中已不存在于数据库的文章直接跳过
+	for _, hit := range res.Hits {
+		article, ok := byID[hit.ID]
+		if !ok {
+			continue
+		}
+		result.List = append(result.List, &SearchHit{Article: article, Score: hit.Score, Highlights: hit.Highlights})
+	}
+	return result, nil
+}
+
+// 文章写入后同步全文索引，只有已发布的文章会被索引
+// 索引失败只记录日志，不影响写入本身，可通过 rebuild-index 子命令修复
+func (svc *Service) syncSearchIndex(articleID uint32) {
+	if global.SearchIndex == nil || articleID == 0 {
+		return
+	}
+	err := svc.indexArticle(articleID)
+	if err != nil {
+		global.Logger.WithContext(svc.ctx).Errorf("svc.syncSearchIndex article %d err: %v", articleID, err)
+	}
+}
+
+// 标签改名或删除后，重新索引该标签下的文章以更新标签分面
+func (svc *Service) syncSearchIndexByTag(tagID uint32) {
+	if global.SearchIndex == nil {
+		return
+	}
+	var cursor *app.Cursor
+	for {
+		articles, err := svc.dao.GetArticleListByCursor(tagID, model.ARTICLE_STATE_PUBLISHED, "", cursor, rebuildBatchSize)
+		if err != nil {
+			global.Logger.WithContext(svc.ctx).Errorf("svc.syncSearchIndexByTag tag %d err: %v", tagID, err)
+			return
+		}
+		if len(articles) == 0 {
+			return
+		}
+		for _, article := range articles {
+			svc.syncSearchIndex(article.ID)
+		}
+		last := articles[len(articles)-1]
+		cursor = &app.Cursor{CreatedOn: last.CreatedOn, ID: last.ID}
+	}
+}
+
+func (svc *Service) indexArticle(articleID uint32) error {
+	articles, err := svc.dao.GetArticlesByIDs([]uint32{articleID}, model.ARTICLE_STATE_PUBLISHED)
+	if err != nil {
+		return err
+	}
+	if len(articles) == 0 {
+		return global.SearchIndex.Delete(articleID)
+	}
+	tags, err := svc.getArticleTags([]uint32{articleID})
+	if err != nil {
+		return err
+	}
+	return global.SearchIndex.Index(newSearchDocument(articles[0], tags[articleID]))
+}
+
+// 根据数据库中已发布的文章重建全文索引，返回索引的文章数
+func (svc *Service) RebuildSearchIndex(path string) (int, error) {
+	count := 0
+	err := search.Rebuild(path, func(add func(*search.Document) error) error {
+		var cursor *app.Cursor
+		for {
+			articles, err := svc.dao.GetArticleListByCursor(0, model.ARTICLE_STATE_PUBLISHED, "", cursor, rebuildBatchSize)
+			if err != nil {
+				return err
+			}
+			if len(articles) == 0 {
+				return nil
+			}
+			articleIDs := make([]uint32, 0, len(articles))
+			for _, article := range articles {
+				articleIDs = append(articleIDs, article.ID)
+			}
+			tags, err := svc.getArticleTags(articleIDs)
+			if err != nil {
+				return err
+			}
+			for _, article := range articles {
+				if err := add(newSearchDocument(article, tags[article.ID])); err != nil {
+					return err
+				}
+				count++
+			}
+			last := articles[len(articles)-1]
+			cursor = &app.Cursor{CreatedOn: last.CreatedOn, ID: last.ID}
+		}
+	})
+	return count, err
+}
+
+func newSearchDocument(article *model.Article, tags []*model.Tag) *search.Document {
+	doc := &search.Document{
+		ID:      article.ID,
+		Title:   article.Title,
+		Desc:    article.Desc,
+		Content: article.Content,
+		Tags:    make([]string, 0, len(tags)),
+	}
+	for _, tag := range tags {
+		doc.Tags = append(doc.Tags, tag.Name)
+	}
+	return doc
+}
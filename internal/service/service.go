@@ -4,8 +4,11 @@ import (
 	"blog-service/global"
 	"blog-service/internal/dao"
 	"context"
+	"errors"
 )
 
+var ErrForbidden = errors.New("permission denied")
+
 type Service struct {
 	ctx context.Context
 	dao *dao.Dao
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"blog-service/global"
+	"blog-service/internal/model"
+	"blog-service/pkg/sitemap"
+)
+
+var ErrSitemapNotFound = errors.New("sitemap not found")
+
+// 生成好的站点地图，文章或标签写入后失效，下次请求时重新生成
+// 缓存在进程内，多实例部署时其他实例的写入要等本实例重启或本实例有写入才会生效
+var sitemapCache struct {
+	sync.Mutex
+	// pages 为按 MaxURLs 拆分后的各个 urlset，index 在只有一页时为空
+	pages [][]byte
+	index []byte
+	valid bool
+}
+
+func invalidateSitemap() {
+	sitemapCache.Lock()
+	sitemapCache.valid = false
+	sitemapCache.pages, sitemapCache.index = nil, nil
+	sitemapCache.Unlock()
+}
+
+// 获取 /sitemap.xml，URL 数量超过 MaxURLs 时返回 sitemap 索引
+func (svc *Service) GetSitemap() ([]byte, error) {
+	pages, index, err := svc.loadSitemap()
+	if err != nil {
+		return nil, err
+	}
+	if index != nil {
+		return index, nil
+	}
+	return pages[0], nil
+}
+
+// 获取拆分后的第 n 页，n 从 1 开始，没有拆分时 /sitemap.xml 即为全部内容，不提供分页
+func (svc *Service) GetSitemapPage(n int) ([]byte, error) {
+	pages, index, err := svc.loadSitemap()
+	if err != nil {
+		return nil, err
+	}
+	if index == nil || n < 1 || n > len(pages) {
+		return nil, ErrSitemapNotFound
+	}
+	return pages[n-1], nil
+}
+
+func (svc *Service) loadSitemap() ([][]byte, []byte, error) {
+	sitemapCache.Lock()
+	defer sitemapCache.Unlock()
+	if sitemapCache.valid {
+		return sitemapCache.pages, sitemapCache.index, nil
+	}
+	pages, index, err := svc.buildSitemap()
+	if err != nil {
+		return nil, nil, err
+	}
+	sitemapCache.pages, sitemapCache.index, sitemapCache.valid = pages, index, true
+	return pages, index, nil
+}
+
+func (svc *Service) buildSitemap() ([][]byte, []byte, error) {
+	siteURL := global.SiteSetting.Load().URL
+	articles, err := svc.dao.GetArticleModifiedList(model.ARTICLE_STATE_PUBLISHED)
+	if err != nil {
+		return nil, nil, err
+	}
+	tags, err := svc.dao.GetTagModifiedList(model.STATE_OPEN)
+	if err != nil {
+		return nil, nil, err
+	}
+	urls := make([]sitemap.URL, 0, len(articles)+len(tags))
+	urls = appendSitemapURLs(urls, siteURL+"/articles/", articles)
+	urls = appendSitemapURLs(urls, siteURL+"/tags/", tags)
+
+	var pages [][]byte
+	var lastMods []time.Time
+	for start := 0; start == 0 || start < len(urls); start += sitemap.MaxURLs {
+		end := min(start+sitemap.MaxURLs, len(urls))
+		var buf bytes.Buffer
+		if err := sitemap.WriteURLSet(&buf, urls[start:end]); err != nil {
+			return nil, nil, err
+		}
+		pages = append(pages, buf.Bytes())
+		lastMods = append(lastMods, latestLastMod(urls[start:end]))
+	}
+	if len(pages) == 1 {
+		return pages, nil, nil
+	}
+	entries := make([]sitemap.URL, 0, len(pages))
+	for i := range pages {
+		entries = append(entries, sitemap.URL{
+			Loc:     siteURL + "/sitemaps/" + strconv.Itoa(i+1) + ".xml",
+			LastMod: sitemap.LastMod(lastMods[i]),
+		})
+	}
+	var buf bytes.Buffer
+	if err := sitemap.WriteIndex(&buf, entries); err != nil {
+		return nil, nil, err
+	}
+	return pages, buf.Bytes(), nil
+}
+
+func appendSitemapURLs(urls []sitemap.URL, prefix string, rows []*model.ModifiedRow) []sitemap.URL {
+	for _, row := range rows {
+		var lastMod time.Time
+		if row.ModifiedOn > 0 {
+			lastMod = time.Unix(int64(row.ModifiedOn), 0)
+		}
+		urls = append(urls, sitemap.URL{
+			Loc:     slugURL(prefix, row.ID, row.Slug),
+			LastMod: sitemap.LastMod(lastMod),
+		})
+	}
+	return urls
+}
+
+func latestLastMod(urls []sitemap.URL) time.Time {
+	var latest time.Time
+	for _, u := range urls {
+		if t, err := time.Parse(time.RFC3339, u.LastMod); err == nil && t.After(latest) {
+			latest = t
+		}
+	}
+	return latest
+}
@@ -0,0 +1,195 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"blog-service/internal/dao"
+	"blog-service/internal/model"
+	"blog-service/pkg/slug"
+	"gorm.io/gorm"
+)
+
+// generate-slugs 子命令记录的修改人
+const generateSlugsName = "generate-slugs"
+
+type ArticleSlugRequest struct {
+	Slug  string `form:"slug" binding:"required,max=100"`
+	State uint8  `form:"state,default=1" binding:"oneof=0 1 2 3 4"`
+}
+
+type TagSlugRequest struct {
+	Slug  string `form:"slug" binding:"required,max=100"`
+	State uint8  `form:"state,default=1" binding:"oneof=0 1"`
+}
+
+// 按 slug 查询文章，slug 是文章的旧地址时返回文章当前的 slug，由调用方重定向
+func (svc *Service) GetArticleBySlug(param *ArticleSlugRequest) (*ArticleDetail, string, error) {
+	state, createdBy := svc.articleReadScope(param.State)
+	article, err := svc.dao.GetArticleBySlug(param.Slug, state)
+	if err == nil && !articleVisible(&article, state, createdBy) {
+		return nil, "", ErrArticleNotFound
+	}
+	if err == nil {
+		detail, err := svc.newArticleDetail(&article)
+		return detail, "", err
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, "", err
+	}
+	redirect, err := svc.dao.GetSlugRedirect(model.SLUG_KIND_ARTICLE, param.Slug)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, "", ErrArticleNotFound
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	// 不对读者不可见的文章重定向，避免泄露未发布文章的 slug
+	article, err = svc.dao.GetArticleByID(redirect.TargetID)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && !articleVisible(&article, state, createdBy)) {
+		return nil, "", ErrArticleNotFound
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	return nil, article.Slug, nil
+}
+
+// 按 slug 查询标签，slug 是标签的旧地址时返回标签当前的 slug，由调用方重定向
+func (svc *Service) GetTagBySlug(param *TagSlugRequest) (*model.Tag, string, error) {
+	tag, err := svc.dao.GetTagBySlug(param.Slug, param.State)
+	if err == nil {
+		return &tag, "", nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, "", err
+	}
+	redirect, err := svc.dao.GetSlugRedirect(model.SLUG_KIND_TAG, param.Slug)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, "", ErrTagNotFound
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	// 与直接查询一样按状态过滤，避免通过旧 slug 探测停用标签的当前 slug
+	tag, err = svc.dao.GetTag(redirect.TargetID, param.State)
+	if err != nil {
+		return nil, "", err
+	}
+	if tag.Model == nil {
+		return nil, "", ErrTagNotFound
+	}
+	return nil, tag.Slug, nil
+}
+
+// 由标题生成 id 对象可用的 slug，已被占用时追加 -2、-3 等序号，标题无法转换时以 kind 作为 slug
+// 需要在写入对象的同一个事务中调用，并发写入相同 slug 时由唯一索引兜底
+func uniqueSlug(tx *dao.Dao, kind, title string, id uint32) (string, error) {
+	base := slug.Make(title)
+	if base == "" {
+		base = kind
+	}
+	candidate := base
+	for i := 2; ; i++ {
+		taken, err := tx.IsSlugTaken(kind, candidate, id)
+		if err != nil {
+			return "", err
+		}
+		if !taken {
+			return candidate, nil
+		}
+		candidate = fmt.Sprintf("%s-%d", base, i)
+	}
+}
+
+// 标题修改后重新生成 slug，并记录旧 slug 的重定向，slug 不变时返回空字符串
+func changeSlug(tx *dao.Dao, kind, oldSlug, title string, id uint32, modifiedBy string) (string, error) {
+	newSlug, err := uniqueSlug(tx, kind, title, id)
+	if err != nil || newSlug == oldSlug {
+		return "", err
+	}
+	// 改回曾经使用过的 slug 时，该 slug 不再需要重定向
+	if err := tx.DeleteSlugRedirect(kind, newSlug, id); err != nil {
+		return "", err
+	}
+	if err := tx.CreateSlugRedirect(kind, oldSlug, id, modifiedBy); err != nil {
+		return "", err
+	}
+	return newSlug, nil
+}
+
+// 为升级前以 ID 作为 slug 的文章和标签按标题重新生成 slug，旧的 ID 地址会重定向到新地址
+func (svc *Service) GenerateSlugs() (int, int, error) {
+	articles, err := svc.generateArticleSlugs()
+	if err != nil {
+		return articles, 0, err
+	}
+	tags, err := svc.generateTagSlugs()
+	return articles, tags, err
+}
+
+func (svc *Service) generateArticleSlugs() (int, error) {
+	count := 0
+	var afterID uint32
+	for {
+		articles, err := svc.dao.GetArticlesAfterID(afterID, rebuildBatchSize)
+		if err != nil || len(articles) == 0 {
+			return count, err
+		}
+		for _, article := range articles {
+			if article.Slug != strconv.FormatUint(uint64(article.ID), 10) {
+				continue
+			}
+			err := svc.dao.Transaction(func(tx *dao.Dao) error {
+				newSlug, err := changeSlug(tx, model.SLUG_KIND_ARTICLE, article.Slug, article.Title, article.ID, generateSlugsName)
+				if err != nil || newSlug == "" {
+					return err
+				}
+				count++
+				return tx.UpdateArticle(&dao.Article{ID: article.ID, Slug: newSlug, ModifiedBy: article.ModifiedBy})
+			})
+			if err != nil {
+				return count, err
+			}
+		}
+		afterID = articles[len(articles)-1].ID
+	}
+}
+
+func (svc *Service) generateTagSlugs() (int, error) {
+	count := 0
+	var afterID uint32
+	for {
+		tags, err := svc.dao.GetTagsAfterID(afterID, rebuildBatchSize)
+		if err != nil || len(tags) == 0 {
+			return count, err
+		}
+		for _, tag := range tags {
+			if tag.Slug != strconv.FormatUint(uint64(tag.ID), 10) {
+				continue
+			}
+			err := svc.dao.Transaction(func(tx *dao.Dao) error {
+				newSlug, err := changeSlug(tx, model.SLUG_KIND_TAG, tag.Slug, tag.Name, tag.ID, generateSlugsName)
+				if err != nil || newSlug == "" {
+					return err
+				}
+				count++
+				return tx.UpdateTag(tag.ID, "", newSlug, 0, "")
+			})
+			if err != nil {
+				return count, err
+			}
+		}
+		afterID = tags[len(tags)-1].ID
+	}
+}
+
+// 面向读者的地址，prefix 形如 {Site.URL}/articles/，没有 slug 时使用 ID
+func slugURL(prefix string, id uint32, slug string) string {
+	if slug == "" {
+		return prefix + strconv.FormatUint(uint64(id), 10)
+	}
+	return prefix + url.PathEscape(slug)
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"testing"
+
+	"blog-service/global"
+	"blog-service/internal/dao"
+	"blog-service/internal/migration"
+	"blog-service/internal/model"
+	"blog-service/pkg/setting"
+	"gorm.io/gorm"
+)
+
+// 在内存 sqlite 上执行全部迁移，返回数据库连接和基于它的 Dao
+func newTestDao(t *testing.T) (*gorm.DB, *dao.Dao) {
+	t.Helper()
+	if global.ServerSetting.Load() == nil {
+		global.ServerSetting.Store(&setting.ServerSettingS{RunMode: "test"})
+	}
+	db, err := model.NewDBEngine(&setting.DatabaseSettingS{DBType: "sqlite", DBName: ":memory:", MaxOpenConns: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	m, err := migration.NewMigrator(db, "sqlite")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Up(); err != nil {
+		t.Fatal(err)
+	}
+	return db, dao.New(db)
+}
+
+func TestUniqueSlug(t *testing.T) {
+	db, d := newTestDao(t)
+	// id 1 和 2 占用 hello-world 和 hello-world-2，id 3 已删除但仍占用 hello-world-3
+	for _, stmt := range []string{
+		"INSERT INTO blog_article (id, title, slug) VALUES (1, 'Hello World', 'hello-world')",
+		"INSERT INTO blog_article (id, title, slug) VALUES (2, 'Hello World', 'hello-world-2')",
+		"INSERT INTO blog_article (id, title, slug, is_del) VALUES (3, 'Hello World', 'hello-world-3', 1)",
+		"INSERT INTO blog_article (id, title, slug) VALUES (4, 'Renamed', 'renamed')",
+		"INSERT INTO blog_slug_redirect (kind, old_slug, target_id) VALUES ('article', 'old-title', 4)",
+		"INSERT INTO blog_tag (id, name, slug) VALUES (1, 'Go 语言', 'go-yu-yan')",
+	} {
+		if err := db.Exec(stmt).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name  string
+		kind  string
+		title string
+		id    uint32
+		want  string
+	}{
+		{"free", model.SLUG_KIND_ARTICLE, "Something New", 0, "something-new"},
+		{"taken by others", model.SLUG_KIND_ARTICLE, "Hello, World!", 0, "hello-world-4"},
+		{"own slug kept", model.SLUG_KIND_ARTICLE, "Hello World", 1, "hello-world"},
+		{"own suffixed slug kept", model.SLUG_KIND_ARTICLE, "Hello World", 2, "hello-world-2"},
+		{"old slug of another object", model.SLUG_KIND_ARTICLE, "Old Title", 0, "old-title-2"},
+		{"own old slug reused", model.SLUG_KIND_ARTICLE, "Old Title", 4, "old-title"},
+		{"kinds are separate", model.SLUG_KIND_ARTICLE, "Go 语言", 0, "go-yu-yan"},
+		{"tag taken", model.SLUG_KIND_TAG, "Go语言", 0, "go-yu-yan-2"},
+		{"untranslatable title", model.SLUG_KIND_TAG, "!!!", 0, "tag"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := uniqueSlug(d, tt.kind, tt.title, tt.id)
+			if err != nil {
+				t.Fatalf("uniqueSlug err: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("uniqueSlug(%q, %q, %d) = %q, want %q", tt.kind, tt.title, tt.id, got, tt.want)
+			}
+		})
+	}
+}
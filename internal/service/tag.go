@@ -1,8 +1,12 @@
 package service
 
 import (
+	"errors"
+
+	"blog-service/internal/dao"
 	"blog-service/internal/model"
 	"blog-service/pkg/app"
+	"gorm.io/gorm"
 )
 
 type CountTagRequest struct {
@@ -16,16 +20,14 @@ type TagListRequest struct {
 }
 
 type CreateTagRequest struct {
-	Name      string `form:"name" binding:"required,min=2,max=100"`
-	CreatedBy string `form:"created_by" binding:"required,min=2,max=100"`
-	State     uint8  `form:"state,default=1" binding:"oneof=0 1"`
+	Name  string `form:"name" binding:"required,min=2,max=100"`
+	State uint8  `form:"state,default=1" binding:"oneof=0 1"`
 }
 
 type UpdateTagRequest struct {
-	ID         uint32 `form:"id" binding:"required,gte=1"`
-	Name       string `form:"name" binding:"max=100"`
-	State      uint8  `form:"state" binding:"required,oneof=0 1"`
-	ModifiedBy string `form:"modified_by" binding:"required,min=3,max=100"`
+	ID    uint32 `form:"id" binding:"required,gte=1"`
+	Name  string `form:"name" binding:"max=100"`
+	State uint8  `form:"state" binding:"required,oneof=0 1"`
 }
 
 type DeleteTagRequest struct {
@@ -40,14 +42,82 @@ func (svc *Service) GetTagList(param *TagListRequest, pager *app.Pager) ([]*mode
 	return svc.dao.GetTagList(param.Name, param.State, pager.Page, pager.PageSize)
 }
 
+func (svc *Service) GetTagCursorList(param *TagListRequest, pager *app.CursorPager) ([]*model.Tag, error) {
+	tags, err := svc.dao.GetTagListByCursor(param.Name, param.State, pager.Cursor, pager.Limit)
+	if err != nil {
+		return nil, err
+	}
+	return app.CursorPage(pager, tags), nil
+}
+
 func (svc *Service) CreateTag(param *CreateTagRequest) error {
-	return svc.dao.CreateTag(param.Name, param.State, param.CreatedBy)
+	principal := app.PrincipalFromContext(svc.ctx)
+	if !principal.Can(app.PermTagManage) {
+		return ErrForbidden
+	}
+	defer invalidateSitemap()
+	return svc.dao.Transaction(func(tx *dao.Dao) error {
+		slug, err := uniqueSlug(tx, model.SLUG_KIND_TAG, param.Name, 0)
+		if err != nil {
+			return err
+		}
+		return tx.CreateTag(param.Name, slug, param.State, principal.Name)
+	})
 }
 
 func (svc *Service) UpdateTag(param *UpdateTagRequest) error {
-	return svc.dao.UpdateTag(param.ID, param.Name, param.State, param.ModifiedBy)
+	principal := app.PrincipalFromContext(svc.ctx)
+	if !principal.Can(app.PermTagManage) {
+		return ErrForbidden
+	}
+	tag, err := svc.dao.GetTagByID(param.ID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrTagNotFound
+	}
+	if err != nil {
+		return err
+	}
+	defer invalidateSitemap()
+	err = svc.dao.Transaction(func(tx *dao.Dao) error {
+		// 名称修改后重新生成 slug，旧 slug 重定向到新 slug
+		var slug string
+		if param.Name != "" && param.Name != tag.Name {
+			var err error
+			slug, err = changeSlug(tx, model.SLUG_KIND_TAG, tag.Slug, param.Name, param.ID, principal.Name)
+			if err != nil {
+				return err
+			}
+		}
+		return tx.UpdateTag(param.ID, param.Name, slug, param.State, principal.Name)
+	})
+	if err != nil {
+		return err
+	}
+	svc.syncSearchIndexByTag(param.ID)
+	return nil
 }
 
 func (svc *Service) DeleteTag(param *DeleteTagRequest) error {
-	return svc.dao.DeleteTag(param.ID)
+	if !app.PrincipalFromContext(svc.ctx).Can(app.PermTagManage) {
+		return ErrForbidden
+	}
+	// 删除关联前记下受影响的文章，删除后逐篇更新搜索索引
+	articleIDs, err := svc.dao.GetTagArticleIDs(param.ID)
+	if err != nil {
+		return err
+	}
+	defer invalidateSitemap()
+	err = svc.dao.Transaction(func(tx *dao.Dao) error {
+		if err := tx.DeleteTag(param.ID); err != nil {
+			return err
+		}
+		return tx.DeleteTagArticles(param.ID)
+	})
+	if err != nil {
+		return err
+	}
+	for _, articleID := range articleIDs {
+		svc.syncSearchIndex(articleID)
+	}
+	return nil
 }
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"os"
+
+	"blog-service/global"
+	"blog-service/pkg/upload"
+)
+
+// 扩展名、大小或内容不符合要求，属于客户端的输入错误
+var ErrInvalidUpload = errors.New("invalid upload file")
+
+type FileInfo struct {
+	Name      string `json:"name"`
+	AccessUrl string `json:"access_url"`
+}
+
+func (svc *Service) UploadFile(fileType upload.FileType, file multipart.File, fileHeader *multipart.FileHeader) (*FileInfo, error) {
+	if !upload.CheckContainExt(fileType, fileHeader.Filename) {
+		return nil, fmt.Errorf("%w: file suffix is not supported", ErrInvalidUpload)
+	}
+	if upload.CheckMaxSize(fileType, fileHeader.Size) {
+		return nil, fmt.Errorf("%w: exceeded maximum file limit", ErrInvalidUpload)
+	}
+	uploadSavePath := upload.GetSavePath()
+	if upload.CheckSavePath(uploadSavePath) {
+		if err := upload.CreateSavePath(uploadSavePath, os.ModePerm); err != nil {
+			return nil, errors.New("failed to create save directory")
+		}
+	}
+	if upload.CheckPermission(uploadSavePath) {
+		return nil, errors.New("insufficient file permissions")
+	}
+
+	// multipart 头中的大小由客户端提供，按实际读取的字节数再校验一次
+	maxSize := int64(global.AppSetting.Load().UploadImageMaxSize) * 1024 * 1024
+	content, err := upload.ReadFile(file, maxSize)
+	if err != nil {
+		return nil, err
+	}
+	if upload.CheckMaxSize(fileType, int64(len(content))) {
+		return nil, fmt.Errorf("%w: exceeded maximum file limit", ErrInvalidUpload)
+	}
+	if !upload.CheckContentType(fileType, fileHeader.Filename, content) {
+		return nil, fmt.Errorf("%w: file content does not match its suffix", ErrInvalidUpload)
+	}
+	fileName := upload.GetFileName(content, upload.GetFileExt(fileHeader.Filename))
+	dst := uploadSavePath + "/" + fileName
+	if err := upload.SaveFile(content, dst); err != nil {
+		return nil, err
+	}
+	accessUrl := upload.GetServerUrl() + "/" + fileName
+	return &FileInfo{Name: fileName, AccessUrl: accessUrl}, nil
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"errors"
+
+	"blog-service/internal/dao"
+	"blog-service/internal/model"
+	"blog-service/pkg/app"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrUserNotFound   = errors.New("user not found")
+	ErrUsernameExists = errors.New("username already exists")
+)
+
+type UserListRequest struct {
+	Role string `form:"role" binding:"omitempty,oneof=author editor admin"`
+}
+
+type CreateUserRequest struct {
+	Username string `form:"username" binding:"required,min=2,max=100,excludes=:"`
+	Password string `form:"password" binding:"required,min=8,max=72"`
+	Role     string `form:"role" binding:"required,oneof=author editor admin"`
+}
+
+type UpdateUserRequest struct {
+	ID       uint32 `form:"id" binding:"required,gte=1"`
+	Password string `form:"password" binding:"omitempty,min=8,max=72"`
+	Role     string `form:"role" binding:"omitempty,oneof=author editor admin"`
+	State    *uint8 `form:"state" binding:"omitempty,oneof=0 1"`
+}
+
+func (svc *Service) CountUser(param *UserListRequest) (int64, error) {
+	return svc.dao.CountUser(param.Role)
+}
+
+func (svc *Service) GetUserList(param *UserListRequest, pager *app.Pager) ([]*model.User, error) {
+	return svc.dao.GetUserList(param.Role, pager.Page, pager.PageSize)
+}
+
+func (svc *Service) CreateUser(param *CreateUserRequest) (*model.User, error) {
+	principal := app.PrincipalFromContext(svc.ctx)
+	if !principal.Can(app.PermUserManage) {
+		return nil, ErrForbidden
+	}
+	_, err := svc.dao.GetUserByUsername(param.Username)
+	if err == nil {
+		return nil, ErrUsernameExists
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(param.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+	return svc.dao.CreateUser(&dao.User{
+		Username:  param.Username,
+		Password:  string(hash),
+		Role:      param.Role,
+		CreatedBy: principal.Name,
+	})
+}
+
+// 管理员不能降低自己的角色或禁用自己，避免系统中没有可用的管理员
+func (svc *Service) UpdateUser(param *UpdateUserRequest) error {
+	principal := app.PrincipalFromContext(svc.ctx)
+	if !principal.Can(app.PermUserManage) {
+		return ErrForbidden
+	}
+	if param.ID == principal.UserID {
+		if (param.Role != "" && param.Role != app.RoleAdmin) || (param.State != nil && *param.State != model.STATE_OPEN) {
+			return ErrForbidden
+		}
+	}
+	_, err := svc.dao.GetUser(param.ID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrUserNotFound
+	}
+	if err != nil {
+		return err
+	}
+	user := &dao.User{ID: param.ID, Role: param.Role, State: param.State, ModifiedBy: principal.Name}
+	if param.Password != "" {
+		hash, err := bcrypt.GenerateFromPassword([]byte(param.Password), bcrypt.DefaultCost)
+		if err != nil {
+			return err
+		}
+		user.Password = string(hash)
+	}
+	return svc.dao.UpdateUser(user)
+}
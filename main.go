@@ -1,16 +1,27 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"log"
 	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
 	"time"
 
 	"blog-service/global"
 	"blog-service/internal/model"
 	"blog-service/internal/routers"
+	"blog-service/pkg/health"
 	"blog-service/pkg/logger"
+	"blog-service/pkg/metrics"
+	"blog-service/pkg/search"
 	"blog-service/pkg/setting"
+	"blog-service/pkg/upload"
 
 	"github.com/gin-gonic/gin"
 	"gopkg.in/natefinch/lumberjack.v2"
@@ -20,66 +31,145 @@ var (
 	ServerSetting   *setting.ServerSettingS
 	AppSetting      *setting.AppSettingS
 	DatabaseSetting *setting.DatabaseSettingS
+
+	config string
 )
 
 func init() {
+	flag.StringVar(&config, "config", "configs/", "指定要使用的配置文件路径，可以是目录或 yaml 文件，多个用逗号分隔")
+}
+
+// 初始化配置、日志和数据库连接，HTTP 服务和子命令共用
+func setup() {
 	err := setupSetting()
 	if err != nil {
-		log.Fatal("init.setupSetting err: %v", err)
+		log.Fatalf("setup.setupSetting err: %v", err)
 	}
 
 	err = setupLogger()
 	if err != nil {
-		log.Fatalf("init.setupLogger err: %v", err)
+		log.Fatalf("setup.setupLogger err: %v", err)
 	}
 
 	err = setupDBEngine()
 	if err != nil {
-		log.Fatalf("init.setupDBEngine err: %v", err)
+		log.Fatalf("setup.setupDBEngine err: %v", err)
 	}
 }
 
 func setupSetting() error {
-	setting, err := setting.NewSetting()
+	s, err := setting.NewSetting(strings.Split(config, ",")...)
+	if err != nil {
+		return err
+	}
+	err = setting.ReadSection(s, "Server", &global.ServerSetting)
+	if err != nil {
+		return err
+	}
+	err = setting.ReadSection(s, "App", &global.AppSetting)
+	if err != nil {
+		return err
+	}
+	err = setting.ReadSection(s, "Log", &global.LogSetting)
+	if err != nil {
+		return err
+	}
+	err = setting.ReadSection(s, "Database", &global.DatabaseSetting)
 	if err != nil {
 		return err
 	}
-	err = setting.ReadSection("Server", &global.ServerSetting)
+	err = setting.ReadSection(s, "JWT", &global.JWTSetting)
 	if err != nil {
 		return err
 	}
-	err = setting.ReadSection("App", &global.AppSetting)
+	err = setting.ReadSection(s, "Limiter", &global.LimiterSetting)
 	if err != nil {
 		return err
 	}
-	err = setting.ReadSection("Database", &global.DatabaseSetting)
+	err = setting.ReadSection(s, "Site", &global.SiteSetting)
 	if err != nil {
 		return err
 	}
-	global.ServerSetting.ReadTimeout *= time.Second
-	global.ServerSetting.WriteTimeout *= time.Second
+	err = setting.ReadSection(s, "Search", &global.SearchSetting)
+	if err != nil {
+		return err
+	}
+	err = setting.ReadSection(s, "Scheduler", &global.SchedulerSetting)
+	if err != nil {
+		return err
+	}
+	// 一次性列出所有配置问题，避免改一处启动一次
+	if err := s.Validate(); err != nil {
+		return err
+	}
+	// 配置文件变化时各配置段会在校验通过后重新读取，需要应用新的日志级别
+	setting.OnChange(func() {
+		if level, err := logger.ParseLevel(global.LogSetting.Load().Level); err == nil {
+			global.Logger.SetLevel(level)
+		}
+		global.Logger.Infof("setting reloaded")
+	})
 	return nil
 }
 
-func setupDBEngine() error {
-	// debug
-	fmt.Println("DatabaseSetting before NewDBEngine:", global.DatabaseSetting)
+// 全文索引只在 HTTP 服务中打开，索引文件同一时间只能被一个进程持有
+func setupSearchIndex() error {
 	var err error
-	global.DBEngine, err = model.NewDBEngine(global.DatabaseSetting)
+	global.SearchIndex, err = search.Open(global.SearchSetting.Load().IndexPath)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
+func setupDBEngine() error {
+	var err error
+	global.DBEngine, err = model.NewDBEngine(global.DatabaseSetting.Load())
+	if err != nil {
+		return err
+	}
+	sqlDB, err := global.DBEngine.DB()
+	if err != nil {
+		return err
+	}
+	return metrics.RegisterDBStats(sqlDB, global.DatabaseSetting.Load().DBName)
+}
+
 func setupLogger() error {
-	fileName := global.AppSetting.LogSavePath + "/" + global.AppSetting.LogFileName + global.AppSetting.LogFileExt
+	logSetting := global.LogSetting.Load()
+	level, err := logger.ParseLevel(logSetting.Level)
+	if err != nil {
+		return err
+	}
+	fileName := logSetting.SavePath + "/" + logSetting.FileName + logSetting.FileExt
 	global.Logger = logger.NewLogger(&lumberjack.Logger{
-		Filename: fileName,
-		MaxSize:  600,
-		MaxAge:   10,
-		Compress: true,
+		Filename:   fileName,
+		MaxSize:    logSetting.MaxSize,
+		MaxAge:     logSetting.MaxAge,
+		MaxBackups: logSetting.MaxBackups,
+		Compress:   logSetting.Compress,
 	}, "", log.LstdFlags).WithCaller(2) //跳过2层调用，从调用该函数的地方开始计数
+	global.Logger.SetLevel(level)
+	for _, s := range logSetting.Sinks {
+		sinkLevel, err := logger.ParseLevel(s.Level)
+		if err != nil {
+			return err
+		}
+		switch s.Output {
+		case "stdout":
+			global.Logger.AddSink(os.Stdout, sinkLevel)
+		case "stderr":
+			global.Logger.AddSink(os.Stderr, sinkLevel)
+		default:
+			global.Logger.AddSink(&lumberjack.Logger{
+				Filename:   s.Output,
+				MaxSize:    logSetting.MaxSize,
+				MaxAge:     logSetting.MaxAge,
+				MaxBackups: logSetting.MaxBackups,
+				Compress:   logSetting.Compress,
+			}, sinkLevel)
+		}
+	}
 	return nil
 }
 
@@ -88,6 +178,50 @@ func setupLogger() error {
 // @description Go 语言编程之旅：一起用 Go 做项目
 // @termsOfService https://github.com/go-programming-tour-book
 func main() {
+	flag.Usage = usage
+	flag.Parse()
+	setup()
+	switch cmd := flag.Arg(0); cmd {
+	case "", "serve":
+		runServer()
+	case "migrate":
+		if err := runMigrate(flag.Args()[1:]); err != nil {
+			log.Fatalf("migrate err: %v", err)
+		}
+	case "rebuild-index":
+		if err := runRebuildIndex(); err != nil {
+			log.Fatalf("rebuild-index err: %v", err)
+		}
+	case "render-content":
+		if err := runRenderContent(); err != nil {
+			log.Fatalf("render-content err: %v", err)
+		}
+	case "generate-slugs":
+		if err := runGenerateSlugs(); err != nil {
+			log.Fatalf("generate-slugs err: %v", err)
+		}
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+}
+
+func usage() {
+	fmt.Fprintf(flag.CommandLine.Output(), `Usage: %s [command]
+
+Commands:
+  serve                  启动 HTTP 服务（默认）
+  migrate up             执行所有未执行的数据库迁移
+  migrate down [n]       回滚最近执行的 n 个迁移，默认为 1
+  migrate status         查看数据库迁移状态
+  rebuild-index          根据数据库重建全文索引，需要先停止 HTTP 服务
+  render-content         重新渲染全部文章的 Markdown 正文
+  generate-slugs         为以 ID 作为 slug 的旧文章和标签按标题生成 slug
+`, os.Args[0])
+	flag.PrintDefaults()
+}
+
+func runServer() {
 	// demo
 	//r := gin.Default()
 	//r.GET("/ping", func(c *gin.Context) {
@@ -96,22 +230,84 @@ func main() {
 	//	})
 	//})
 	//r.Run() // listen and serve on 0.0.0.0:8080 (for windows "localhost:8080")
-	gin.SetMode(global.ServerSetting.RunMode)
+	serverSetting := global.ServerSetting.Load()
+	gin.SetMode(serverSetting.RunMode)
+	err := setupSearchIndex()
+	if err != nil {
+		log.Fatalf("setup.setupSearchIndex err: %v", err)
+	}
+	registerHealthChecks()
 	router := routers.NewRouter()
+	schedulerCtx, stopScheduler := context.WithCancel(context.Background())
+	schedulerDone := make(chan struct{})
+	go func() {
+		defer close(schedulerDone)
+		runPublishScheduler(schedulerCtx)
+	}()
 	// 启动服务
 	s := &http.Server{
-		Addr:           ":" + global.ServerSetting.HttpPort,
+		Addr:           ":" + serverSetting.HttpPort,
 		Handler:        router,
-		ReadTimeout:    10 * time.Second,
-		WriteTimeout:   10 * time.Second,
+		ReadTimeout:    serverSetting.ReadTimeout,
+		WriteTimeout:   serverSetting.WriteTimeout,
 		MaxHeaderBytes: 1 << 20, // 1MB
 	}
-	fmt.Println("start http server listening", global.ServerSetting.HttpPort)
-	// 测试日志
-	global.Logger.Infof("%s: blog-service started", "debug")
-	// debug
-	//fmt.Println("debug: the value of ServerSetting is", global.ServerSetting)
-	//fmt.Println("debug: the value of AppSetting is ", global.AppSetting)
-	//fmt.Println("debug: tht value of DatabaseSetting is ", global.DatabaseSetting)
-	s.ListenAndServe()
+	go func() {
+		fmt.Println("start http server listening", serverSetting.HttpPort)
+		global.Logger.Infof("blog-service started, listening on %s", serverSetting.HttpPort)
+		if err := s.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatalf("s.ListenAndServe err: %v", err)
+		}
+	}()
+
+	// 等待中断信号，收到后在 ShutdownTimeout 内处理完正在进行的请求再退出
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	sig := <-quit
+	global.Logger.Infof("received signal %s, shutting down server", sig)
+	health.SetShuttingDown()
+	// 停机相关的时长按收到信号时的配置
+	serverSetting = global.ServerSetting.Load()
+	time.Sleep(serverSetting.ShutdownDelay)
+
+	ctx, cancel := context.WithTimeout(context.Background(), serverSetting.ShutdownTimeout)
+	defer cancel()
+	if err := s.Shutdown(ctx); err != nil {
+		global.Logger.Errorf("s.Shutdown err: %v", err)
+	}
+	stopScheduler()
+	<-schedulerDone
+	shutdown()
+}
+
+// 数据库由 /readyz 直接检查，这里注册服务运行时依赖的其他资源
+func registerHealthChecks() {
+	health.Register("search_index", func(ctx context.Context) error {
+		if global.SearchIndex == nil {
+			return errors.New("search index is not open")
+		}
+		_, err := global.SearchIndex.DocCount()
+		return err
+	})
+	health.Register("uploads", func(ctx context.Context) error {
+		return upload.CheckWritable(global.AppSetting.Load().UploadSavePath)
+	})
+}
+
+// 释放数据库连接池并关闭日志文件
+func shutdown() {
+	if global.SearchIndex != nil {
+		if err := global.SearchIndex.Close(); err != nil {
+			global.Logger.Errorf("SearchIndex.Close err: %v", err)
+		}
+	}
+	if sqlDB, err := global.DBEngine.DB(); err == nil {
+		if err := sqlDB.Close(); err != nil {
+			global.Logger.Errorf("sqlDB.Close err: %v", err)
+		}
+	}
+	global.Logger.Infof("blog-service exited")
+	if err := global.Logger.Close(); err != nil {
+		log.Printf("global.Logger.Close err: %v", err)
+	}
 }
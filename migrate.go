@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"blog-service/global"
+	"blog-service/internal/migration"
+)
+
+func runMigrate(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing migrate action, expected up, down or status")
+	}
+	migrator, err := migration.NewMigrator(global.DBEngine, global.DatabaseSetting.Load().Dialect())
+	if err != nil {
+		return err
+	}
+	switch args[0] {
+	case "up":
+		done, err := migrator.Up()
+		for _, m := range done {
+			fmt.Printf("applied %d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(done) == 0 {
+			fmt.Println("no pending migrations")
+		}
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps <= 0 {
+				return fmt.Errorf("invalid down steps: %s", args[1])
+			}
+		}
+		done, err := migrator.Down(steps)
+		for _, m := range done {
+			fmt.Printf("rolled back %d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(done) == 0 {
+			fmt.Println("no applied migrations")
+		}
+	case "status":
+		statuses, err := migrator.Status()
+		if err != nil {
+			return err
+		}
+		for _, s := range statuses {
+			state := "pending"
+			if s.Applied {
+				state = "applied at " + time.Unix(int64(s.AppliedOn), 0).Format(time.RFC3339)
+			}
+			fmt.Printf("%04d_%-40s %s\n", s.Migration.Version, s.Migration.Name, state)
+		}
+	default:
+		return fmt.Errorf("unknown migrate action %q, expected up, down or status", args[0])
+	}
+	return nil
+}
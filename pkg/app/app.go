@@ -42,7 +42,15 @@ func (r *Response) ToResponse(data interface{}) {
 // "list" 字段的值为传入的 list 接口参数，表示要返回的列表数据；
 // "pager" 字段的值为一个 Pager 结构体，其中包含了分页信息，包括当前页码、每页大小和总记录数。
 // 这个方法用于构建一个包含列表数据和分页信息的响应，以便在客户端进行展示。
+// 使用游标分页时 "pager" 为 CursorPager，包含 next_cursor 和 prev_cursor，不返回总记录数。
 func (r *Response) ToResponseList(list interface{}, totalRows int64) {
+	if pager, ok := getCursorPager(r.Ctx); ok {
+		r.Ctx.JSON(200, gin.H{
+			"list":  list,
+			"pager": pager,
+		})
+		return
+	}
 	r.Ctx.JSON(200, gin.H{
 		"list": list,
 		"pager": Pager{
@@ -64,6 +72,7 @@ func (r *Response) ToResponseList(list interface{}, totalRows int64) {
 // r.Ctx.JSON(err.StatusCode(), response)：使用 r.Ctx.JSON() 方法将 response 映射以 JSON 格式发送给客户端，
 // 状态码为 err.StatusCode() 获取的状态码。
 func (r *Response) ToErrorResponse(err *errcode.Error) {
+	r.Ctx.Set(errCodeKey, err.Code())
 	response := gin.H{"code": err.Code(), "msg": err.Msg()}
 	details := err.Details()
 	if len(details) > 0 {
@@ -71,3 +80,11 @@ func (r *Response) ToErrorResponse(err *errcode.Error) {
 	}
 	r.Ctx.JSON(err.StatusCode(), response)
 }
+
+// 记录在 gin.Context 中的错误码的键，供指标、访问日志等中间件读取
+const errCodeKey = "errcode"
+
+// 获取 ToErrorResponse 写入的错误码，未返回错误时 ok 为 false
+func GetErrCode(c *gin.Context) (int, bool) {
+	return c.GetInt(errCodeKey), c.Value(errCodeKey) != nil
+}
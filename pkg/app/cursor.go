@@ -0,0 +1,138 @@
+package app
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"blog-service/pkg/convert"
+	"github.com/gin-gonic/gin"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// 游标指向某一行的 (created_on, id)，Prev 为 true 时表示向前翻页
+type Cursor struct {
+	CreatedOn uint32 `json:"c"`
+	ID        uint32 `json:"i"`
+	Prev      bool   `json:"p,omitempty"`
+}
+
+// 可以生成游标的行，列表按 (created_on, id) 倒序排列
+type Keyset interface {
+	CursorKey() (createdOn, id uint32)
+}
+
+// 游标分页器，Cursor 为 nil 时表示第一页
+type CursorPager struct {
+	Cursor     *Cursor `json:"-"`
+	Limit      int     `json:"limit"`
+	NextCursor string  `json:"next_cursor"`
+	PrevCursor string  `json:"prev_cursor"`
+}
+
+const cursorPagerKey = "cursor_pager"
+
+// 请求带有 cursor 或 limit 参数时使用游标分页，否则 ok 为 false，继续使用页码分页
+// 返回的分页器会记录在 gin.Context 中，由 ToResponseList 输出 next_cursor 和 prev_cursor
+func GetCursorPager(c *gin.Context) (pager *CursorPager, ok bool, err error) {
+	token, hasCursor := c.GetQuery("cursor")
+	_, hasLimit := c.GetQuery("limit")
+	if !hasCursor && !hasLimit {
+		return nil, false, nil
+	}
+	pager = &CursorPager{Limit: GetLimit(c)}
+	if token != "" {
+		pager.Cursor, err = DecodeCursor(token)
+		if err != nil {
+			return nil, true, err
+		}
+	}
+	c.Set(cursorPagerKey, pager)
+	return pager, true, nil
+}
+
+func GetLimit(c *gin.Context) int {
+	limit := convert.StrTo(c.Query("limit")).MustInt()
+	if limit <= 0 {
+		return GetPageSize(c)
+	}
+	return clampPageSize(limit)
+}
+
+// 游标为 payload.signature 的形式，签名防止客户端伪造游标
+func EncodeCursor(cursor *Cursor) string {
+	payload, _ := json.Marshal(cursor)
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(signCursor(encoded))
+}
+
+func DecodeCursor(token string) (*Cursor, error) {
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, ErrInvalidCursor
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(sig, signCursor(encoded)) {
+		return nil, ErrInvalidCursor
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var cursor Cursor
+	if err := json.Unmarshal(payload, &cursor); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return &cursor, nil
+}
+
+// 复用 JWT 密钥，加上前缀与 Token 签名区分
+func signCursor(encoded string) []byte {
+	mac := hmac.New(sha256.New, GetJWTSecret())
+	mac.Write([]byte("cursor:" + encoded))
+	return mac.Sum(nil)
+}
+
+// 按游标查询时应多取一条用于判断是否还有更多数据，rows 为按展示顺序排列的查询结果
+// 截掉多取的一条并设置 NextCursor 和 PrevCursor
+func CursorPage[T Keyset](p *CursorPager, rows []T) []T {
+	hasMore := len(rows) > p.Limit
+	backward := p.Cursor != nil && p.Cursor.Prev
+	if hasMore {
+		if backward {
+			// 向前翻页时多取的一条在最前面
+			rows = rows[len(rows)-p.Limit:]
+		} else {
+			rows = rows[:p.Limit]
+		}
+	}
+	p.NextCursor, p.PrevCursor = "", ""
+	if len(rows) == 0 {
+		return rows
+	}
+	first, last := rows[0], rows[len(rows)-1]
+	// 向后翻页且还有数据，或者刚向前翻过页，都说明后面还有数据
+	if backward || hasMore {
+		createdOn, id := last.CursorKey()
+		p.NextCursor = EncodeCursor(&Cursor{CreatedOn: createdOn, ID: id})
+	}
+	// 不是第一页，或者向前翻页时前面还有数据
+	if (!backward && p.Cursor != nil) || (backward && hasMore) {
+		createdOn, id := first.CursorKey()
+		p.PrevCursor = EncodeCursor(&Cursor{CreatedOn: createdOn, ID: id, Prev: true})
+	}
+	return rows
+}
+
+func getCursorPager(c *gin.Context) (*CursorPager, bool) {
+	v, ok := c.Get(cursorPagerKey)
+	if !ok {
+		return nil, false
+	}
+	pager, ok := v.(*CursorPager)
+	return pager, ok
+}
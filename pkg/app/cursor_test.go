@@ -0,0 +1,189 @@
+package app
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"blog-service/global"
+	"blog-service/pkg/setting"
+)
+
+type row struct {
+	createdOn, id uint32
+}
+
+func (r row) CursorKey() (uint32, uint32) {
+	return r.createdOn, r.id
+}
+
+func setSecret(t *testing.T, secret string) {
+	t.Helper()
+	old := global.JWTSetting.Load()
+	global.JWTSetting.Store(&setting.JWTSettingS{Secret: secret})
+	t.Cleanup(func() { global.JWTSetting.Store(old) })
+}
+
+// 按 id 倒序生成 n 行，created_on 与 id 相同
+func makeRows(from, n int) []row {
+	rows := make([]row, 0, n)
+	for i := 0; i < n; i++ {
+		id := uint32(from - i)
+		rows = append(rows, row{createdOn: id, id: id})
+	}
+	return rows
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	setSecret(t, "secret")
+	tests := []*Cursor{
+		{CreatedOn: 1700000000, ID: 42},
+		{CreatedOn: 1700000000, ID: 42, Prev: true},
+		{},
+	}
+	for _, want := range tests {
+		token := EncodeCursor(want)
+		got, err := DecodeCursor(token)
+		if err != nil {
+			t.Fatalf("DecodeCursor(%q) err: %v", token, err)
+		}
+		if *got != *want {
+			t.Errorf("DecodeCursor(%q) = %+v, want %+v", token, got, want)
+		}
+	}
+}
+
+func TestDecodeCursorRejectsTampering(t *testing.T) {
+	setSecret(t, "secret")
+	token := EncodeCursor(&Cursor{CreatedOn: 100, ID: 1})
+	encoded, signature, _ := strings.Cut(token, ".")
+	forged := base64.RawURLEncoding.EncodeToString([]byte(`{"c":100,"i":999}`))
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"empty", ""},
+		{"no signature", encoded},
+		{"payload replaced", forged + "." + signature},
+		{"signature not base64", encoded + ".!!!"},
+		{"signature truncated", encoded + "." + signature[:len(signature)-2]},
+		{"signature empty", encoded + "."},
+		{"payload not json", base64.RawURLEncoding.EncodeToString([]byte("x")) + "." + signature},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := DecodeCursor(tt.token); err != ErrInvalidCursor {
+				t.Errorf("DecodeCursor err = %v, want ErrInvalidCursor", err)
+			}
+		})
+	}
+
+	setSecret(t, "another")
+	if _, err := DecodeCursor(token); err != ErrInvalidCursor {
+		t.Errorf("cursor signed with old secret: err = %v, want ErrInvalidCursor", err)
+	}
+}
+
+func TestCursorPage(t *testing.T) {
+	setSecret(t, "secret")
+	tests := []struct {
+		name     string
+		cursor   *Cursor
+		rows     []row
+		wantIDs  []uint32
+		wantNext *Cursor
+		wantPrev *Cursor
+	}{
+		{
+			name:    "empty first page",
+			rows:    nil,
+			wantIDs: nil,
+		},
+		{
+			name:    "first page without more",
+			rows:    makeRows(10, 3),
+			wantIDs: []uint32{10, 9, 8},
+		},
+		{
+			name:     "first page with more",
+			rows:     makeRows(10, 4),
+			wantIDs:  []uint32{10, 9, 8},
+			wantNext: &Cursor{CreatedOn: 8, ID: 8},
+		},
+		{
+			name:     "middle page",
+			cursor:   &Cursor{CreatedOn: 8, ID: 8},
+			rows:     makeRows(7, 4),
+			wantIDs:  []uint32{7, 6, 5},
+			wantNext: &Cursor{CreatedOn: 5, ID: 5},
+			wantPrev: &Cursor{CreatedOn: 7, ID: 7, Prev: true},
+		},
+		{
+			name:     "last page",
+			cursor:   &Cursor{CreatedOn: 5, ID: 5},
+			rows:     makeRows(4, 2),
+			wantIDs:  []uint32{4, 3},
+			wantPrev: &Cursor{CreatedOn: 4, ID: 4, Prev: true},
+		},
+		{
+			name:    "past the last page",
+			cursor:  &Cursor{CreatedOn: 1, ID: 1},
+			rows:    nil,
+			wantIDs: nil,
+		},
+		{
+			name:     "backward with more",
+			cursor:   &Cursor{CreatedOn: 4, ID: 4, Prev: true},
+			rows:     makeRows(8, 4),
+			wantIDs:  []uint32{7, 6, 5},
+			wantNext: &Cursor{CreatedOn: 5, ID: 5},
+			wantPrev: &Cursor{CreatedOn: 7, ID: 7, Prev: true},
+		},
+		{
+			name:     "backward to the first page",
+			cursor:   &Cursor{CreatedOn: 7, ID: 7, Prev: true},
+			rows:     makeRows(10, 3),
+			wantIDs:  []uint32{10, 9, 8},
+			wantNext: &Cursor{CreatedOn: 8, ID: 8},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &CursorPager{Cursor: tt.cursor, Limit: 3}
+			rows := CursorPage(p, tt.rows)
+
+			var ids []uint32
+			for _, r := range rows {
+				ids = append(ids, r.id)
+			}
+			if len(ids) != len(tt.wantIDs) {
+				t.Fatalf("ids = %v, want %v", ids, tt.wantIDs)
+			}
+			for i := range ids {
+				if ids[i] != tt.wantIDs[i] {
+					t.Fatalf("ids = %v, want %v", ids, tt.wantIDs)
+				}
+			}
+			checkCursor(t, "next", p.NextCursor, tt.wantNext)
+			checkCursor(t, "prev", p.PrevCursor, tt.wantPrev)
+		})
+	}
+}
+
+func checkCursor(t *testing.T, name, token string, want *Cursor) {
+	t.Helper()
+	if want == nil {
+		if token != "" {
+			t.Errorf("%s cursor = %q, want empty", name, token)
+		}
+		return
+	}
+	got, err := DecodeCursor(token)
+	if err != nil {
+		t.Fatalf("%s cursor %q: %v", name, token, err)
+	}
+	if *got != *want {
+		t.Errorf("%s cursor = %+v, want %+v", name, got, want)
+	}
+}
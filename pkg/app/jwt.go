@@ -0,0 +1,67 @@
+package app
+
+import (
+	"time"
+
+	"blog-service/global"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// 写入 JWT 的自定义声明，携带 AppKey 或用户身份，不携带 AppSecret 和密码
+type Claims struct {
+	AppKey string `json:"app_key,omitempty"`
+	UserID uint32 `json:"uid,omitempty"`
+	Name   string `json:"name,omitempty"`
+	Role   string `json:"role,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// 通过 AppKey 签发的 Token 视为管理员，包括未携带角色的旧 Token
+func (c *Claims) Principal() *Principal {
+	if c.UserID == 0 {
+		return NewAppKeyPrincipal(c.AppKey)
+	}
+	return &Principal{UserID: c.UserID, Name: c.Name, Role: c.Role}
+}
+
+func GetJWTSecret() []byte {
+	return []byte(global.JWTSetting.Load().Secret)
+}
+
+// 根据身份签发 Token，签发者和有效期取自 JWT 配置
+// 角色写入 Token 仅供参考，JWTAuth 每次请求按数据库重新加载用户的角色和状态，并确认 AppKey 仍然存在
+func GenerateToken(p *Principal) (string, error) {
+	if p.UserID == 0 {
+		return generateToken(Claims{AppKey: p.AppKey})
+	}
+	return generateToken(Claims{UserID: p.UserID, Name: p.Name, Role: p.Role})
+}
+
+func generateToken(claims Claims) (string, error) {
+	nowTime := time.Now()
+	jwtSetting := global.JWTSetting.Load()
+	claims.RegisteredClaims = jwt.RegisteredClaims{
+		Issuer:    jwtSetting.Issuer,
+		IssuedAt:  jwt.NewNumericDate(nowTime),
+		ExpiresAt: jwt.NewNumericDate(nowTime.Add(jwtSetting.Expire)),
+	}
+	tokenClaims := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return tokenClaims.SignedString([]byte(jwtSetting.Secret))
+}
+
+// 解析并校验 Token，过期时返回的错误满足 errors.Is(err, jwt.ErrTokenExpired)
+func ParseToken(token string) (*Claims, error) {
+	tokenClaims, err := jwt.ParseWithClaims(token, &Claims{}, func(token *jwt.Token) (interface{}, error) {
+		return GetJWTSecret(), nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(global.JWTSetting.Load().Issuer),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if claims, ok := tokenClaims.Claims.(*Claims); ok && tokenClaims.Valid {
+		return claims, nil
+	}
+	return nil, jwt.ErrTokenInvalidClaims
+}
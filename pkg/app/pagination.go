@@ -17,10 +17,14 @@ func GetPage(c *gin.Context) int {
 func GetPageSize(c *gin.Context) int {
 	pageSize := convert.StrTo(c.Query("page_size")).MustInt()
 	if pageSize <= 0 {
-		return global.AppSetting.DefaultPageSize
+		return global.AppSetting.Load().DefaultPageSize
 	}
-	if pageSize > global.AppSetting.MaxPageSize {
-		return global.AppSetting.MaxPageSize
+	return clampPageSize(pageSize)
+}
+
+func clampPageSize(pageSize int) int {
+	if maxPageSize := global.AppSetting.Load().MaxPageSize; pageSize > maxPageSize {
+		return maxPageSize
 	}
 	return pageSize
 }
@@ -0,0 +1,87 @@
+package app
+
+import "context"
+
+// 用户角色
+const (
+	RoleAuthor = "author"
+	RoleEditor = "editor"
+	RoleAdmin  = "admin"
+)
+
+type Permission string
+
+const (
+	PermArticleCreate    Permission = "article:create"
+	PermArticleUpdateOwn Permission = "article:update:own"
+	PermArticleUpdateAny Permission = "article:update:any"
+	PermArticleDeleteOwn Permission = "article:delete:own"
+	PermArticleDeleteAny Permission = "article:delete:any"
+	PermArticlePublish   Permission = "article:publish"
+	PermCommentModerate  Permission = "comment:moderate"
+	PermTagManage        Permission = "tag:manage"
+	PermUserManage       Permission = "user:manage"
+)
+
+// 权限矩阵：作者只能编辑自己的文章，编辑可以编辑、发布任意文章并审核评论，管理员另外可以管理标签和用户
+var rolePermissions = map[string][]Permission{
+	RoleAuthor: {
+		PermArticleCreate, PermArticleUpdateOwn, PermArticleDeleteOwn,
+	},
+	RoleEditor: {
+		PermArticleCreate, PermArticleUpdateOwn, PermArticleDeleteOwn,
+		PermArticleUpdateAny, PermArticleDeleteAny, PermArticlePublish, PermCommentModerate,
+	},
+	RoleAdmin: {
+		PermArticleCreate, PermArticleUpdateOwn, PermArticleDeleteOwn,
+		PermArticleUpdateAny, PermArticleDeleteAny, PermArticlePublish, PermCommentModerate,
+		PermTagManage, PermUserManage,
+	},
+}
+
+func IsValidRole(role string) bool {
+	_, ok := rolePermissions[role]
+	return ok
+}
+
+// 当前请求的身份，由 JWTAuth 根据 Token 写入请求的 context
+// 通过 AppKey 获取的 Token 视为管理员，Name 为 appkey:<AppKey>，用户名不能包含冒号，不会与用户重名
+type Principal struct {
+	UserID uint32
+	AppKey string
+	Name   string
+	Role   string
+}
+
+const appKeyNamePrefix = "appkey:"
+
+func NewAppKeyPrincipal(appKey string) *Principal {
+	return &Principal{AppKey: appKey, Name: appKeyNamePrefix + appKey, Role: RoleAdmin}
+}
+
+// 拥有 perms 中任意一个权限即返回 true
+func (p *Principal) Can(perms ...Permission) bool {
+	if p == nil {
+		return false
+	}
+	for _, granted := range rolePermissions[p.Role] {
+		for _, perm := range perms {
+			if granted == perm {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+type principalKey struct{}
+
+func NewPrincipalContext(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// 未认证的请求返回 nil
+func PrincipalFromContext(ctx context.Context) *Principal {
+	p, _ := ctx.Value(principalKey{}).(*Principal)
+	return p
+}
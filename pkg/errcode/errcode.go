@@ -6,9 +6,9 @@ import (
 )
 
 type Error struct {
-	code    int      `json:"code"`
-	msg     string   `json:"msg"`
-	details []string `json:"details"`
+	code    int
+	msg     string
+	details []string
 }
 
 var (
@@ -18,6 +18,34 @@ var (
 	ErrorUpdateTagFail  = NewError(20010003, "更新标签失败")
 	ErrorDeleteTagFail  = NewError(20010004, "删除标签失败")
 	ErrorCountTagFail   = NewError(20010005, "统计标签失败")
+
+	ErrorGetArticleFail    = NewError(20020001, "获取单个文章失败")
+	ErrorGetArticlesFail   = NewError(20020002, "获取多个文章失败")
+	ErrorCreateArticleFail = NewError(20020003, "创建文章失败")
+	ErrorUpdateArticleFail = NewError(20020004, "更新文章失败")
+	ErrorDeleteArticleFail = NewError(20020005, "删除文章失败")
+
+	ErrorUploadFileFail = NewError(20030001, "上传文件失败")
+
+	ErrorGetFeedFail    = NewError(20040001, "获取订阅源失败")
+	ErrorGetSitemapFail = NewError(20040002, "获取站点地图失败")
+
+	ErrorGetCommentListFail = NewError(20050001, "获取评论列表失败")
+	ErrorCreateCommentFail  = NewError(20050002, "创建评论失败")
+	ErrorUpdateCommentFail  = NewError(20050003, "更新评论失败")
+	ErrorCountCommentFail   = NewError(20050004, "统计评论失败")
+
+	ErrorSearchFail = NewError(20060001, "搜索失败")
+
+	ErrorGetUserListFail = NewError(20070001, "获取用户列表失败")
+	ErrorCreateUserFail  = NewError(20070002, "创建用户失败")
+	ErrorUpdateUserFail  = NewError(20070003, "更新用户失败")
+	ErrorCountUserFail   = NewError(20070004, "统计用户失败")
+
+	ErrorGetRevisionListFail = NewError(20080001, "获取修订列表失败")
+	ErrorGetRevisionFail     = NewError(20080002, "获取修订失败")
+	ErrorDiffRevisionFail    = NewError(20080003, "比较修订失败")
+	ErrorRestoreRevisionFail = NewError(20080004, "恢复修订失败")
 )
 
 func NewError(code int, msg string) *Error {
@@ -75,8 +103,10 @@ func (e *Error) StatusCode() int {
 		return http.StatusUnauthorized
 	case TooManyRequests.Code():
 		return http.StatusTooManyRequests
-		//case NotFound.code():
-		//	return http.stateNotFound
+	case Forbidden.Code():
+		return http.StatusForbidden
+	case NotFound.Code():
+		return http.StatusNotFound
 	}
 	return http.StatusInternalServerError
 }
@@ -0,0 +1,78 @@
+package health
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// 依赖检查函数，返回 nil 表示该依赖可用
+type CheckFunc func(ctx context.Context) error
+
+type Result struct {
+	Name  string `json:"name"`
+	Error string `json:"error,omitempty"`
+}
+
+func (r Result) OK() bool {
+	return r.Error == ""
+}
+
+var (
+	mu           sync.RWMutex
+	checkers     = map[string]CheckFunc{}
+	shuttingDown atomic.Bool
+)
+
+// 注册一个就绪检查，同名检查会被覆盖
+func Register(name string, fn CheckFunc) {
+	mu.Lock()
+	defer mu.Unlock()
+	checkers[name] = fn
+}
+
+func Unregister(name string) {
+	mu.Lock()
+	defer mu.Unlock()
+	delete(checkers, name)
+}
+
+// 并发执行所有已注册的检查，结果按名称排序
+func Check(ctx context.Context) []Result {
+	mu.RLock()
+	names := make([]string, 0, len(checkers))
+	fns := make([]CheckFunc, 0, len(checkers))
+	for name, fn := range checkers {
+		names = append(names, name)
+		fns = append(fns, fn)
+	}
+	mu.RUnlock()
+
+	results := make([]Result, len(names))
+	var wg sync.WaitGroup
+	for i := range names {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = Result{Name: names[i]}
+			if err := fns[i](ctx); err != nil {
+				results[i].Error = err.Error()
+			}
+		}(i)
+	}
+	wg.Wait()
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Name < results[j].Name
+	})
+	return results
+}
+
+// 标记服务进入关闭流程，之后就绪检查一律失败
+func SetShuttingDown() {
+	shuttingDown.Store(true)
+}
+
+func IsShuttingDown() bool {
+	return shuttingDown.Load()
+}
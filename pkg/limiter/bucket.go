@@ -0,0 +1,105 @@
+package limiter
+
+import (
+	"sync"
+	"time"
+)
+
+// 令牌桶：每隔 fillInterval 放入 quantum 个令牌，最多保存 capacity 个
+type Bucket struct {
+	mu           sync.Mutex
+	fillInterval time.Duration
+	capacity     int64
+	quantum      int64
+	available    int64
+	latestTick   int64
+	startTime    time.Time
+	now          func() time.Time
+}
+
+func NewBucket(fillInterval time.Duration, capacity, quantum int64) *Bucket {
+	return newBucketWithClock(fillInterval, capacity, quantum, time.Now)
+}
+
+func newBucketWithClock(fillInterval time.Duration, capacity, quantum int64, now func() time.Time) *Bucket {
+	if fillInterval <= 0 {
+		panic("limiter: fill interval must be positive")
+	}
+	if capacity <= 0 || quantum <= 0 {
+		panic("limiter: capacity and quantum must be positive")
+	}
+	return &Bucket{
+		fillInterval: fillInterval,
+		capacity:     capacity,
+		quantum:      quantum,
+		available:    capacity,
+		startTime:    now(),
+		now:          now,
+	}
+}
+
+func (b *Bucket) Capacity() int64 {
+	return b.capacity
+}
+
+// 当前可用的令牌数
+func (b *Bucket) Available() int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.adjust(b.now())
+	return b.available
+}
+
+// 尝试取出 count 个令牌，返回是否成功以及剩余令牌数
+func (b *Bucket) TakeAvailable(count int64) (bool, int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.adjust(b.now())
+	if count > b.available {
+		return false, b.available
+	}
+	b.available -= count
+	return true, b.available
+}
+
+// 距离桶内至少有 count 个令牌还需等待的时间
+func (b *Bucket) WaitTime(count int64) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := b.now()
+	b.adjust(now)
+	return b.waitTime(now, count)
+}
+
+// 距离桶被重新填满还需等待的时间
+func (b *Bucket) ResetTime() time.Duration {
+	return b.WaitTime(b.capacity)
+}
+
+func (b *Bucket) waitTime(now time.Time, count int64) time.Duration {
+	if count > b.capacity {
+		count = b.capacity
+	}
+	missing := count - b.available
+	if missing <= 0 {
+		return 0
+	}
+	ticks := (missing + b.quantum - 1) / b.quantum
+	next := b.startTime.Add(time.Duration(b.latestTick+ticks) * b.fillInterval)
+	return next.Sub(now)
+}
+
+// 按照经过的时间补充令牌
+func (b *Bucket) adjust(now time.Time) {
+	tick := int64(now.Sub(b.startTime) / b.fillInterval)
+	if tick <= b.latestTick {
+		return
+	}
+	if b.available < b.capacity {
+		b.available += (tick - b.latestTick) * b.quantum
+		if b.available > b.capacity {
+			b.available = b.capacity
+		}
+	}
+	b.latestTick = tick
+}
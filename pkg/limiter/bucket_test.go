@@ -0,0 +1,158 @@
+package limiter
+
+import (
+	"testing"
+	"time"
+)
+
+type fakeClock struct {
+	t time.Time
+}
+
+func (f *fakeClock) now() time.Time {
+	return f.t
+}
+
+func (f *fakeClock) advance(d time.Duration) {
+	f.t = f.t.Add(d)
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{t: time.Unix(1700000000, 0)}
+}
+
+func TestBucketStartsFull(t *testing.T) {
+	clock := newFakeClock()
+	b := newBucketWithClock(time.Second, 3, 1, clock.now)
+	for i := 0; i < 3; i++ {
+		if ok, _ := b.TakeAvailable(1); !ok {
+			t.Fatalf("take %d: want ok", i)
+		}
+	}
+	if ok, remaining := b.TakeAvailable(1); ok || remaining != 0 {
+		t.Fatalf("take from empty bucket: got ok=%v remaining=%d", ok, remaining)
+	}
+}
+
+func TestBucketRefill(t *testing.T) {
+	clock := newFakeClock()
+	b := newBucketWithClock(time.Second, 4, 2, clock.now)
+	b.TakeAvailable(4)
+
+	clock.advance(999 * time.Millisecond)
+	if got := b.Available(); got != 0 {
+		t.Fatalf("before first tick: available = %d, want 0", got)
+	}
+	clock.advance(time.Millisecond)
+	if got := b.Available(); got != 2 {
+		t.Fatalf("after one tick: available = %d, want 2", got)
+	}
+	clock.advance(10 * time.Second)
+	if got := b.Available(); got != 4 {
+		t.Fatalf("refill must not exceed capacity: available = %d, want 4", got)
+	}
+}
+
+func TestBucketWaitTime(t *testing.T) {
+	clock := newFakeClock()
+	b := newBucketWithClock(time.Second, 4, 1, clock.now)
+	b.TakeAvailable(4)
+	clock.advance(300 * time.Millisecond)
+
+	if got, want := b.WaitTime(1), 700*time.Millisecond; got != want {
+		t.Fatalf("WaitTime(1) = %v, want %v", got, want)
+	}
+	if got, want := b.ResetTime(), 3700*time.Millisecond; got != want {
+		t.Fatalf("ResetTime() = %v, want %v", got, want)
+	}
+	clock.advance(700 * time.Millisecond)
+	if ok, _ := b.TakeAvailable(1); !ok {
+		t.Fatal("take after WaitTime elapsed: want ok")
+	}
+}
+
+func TestLimiterPerClient(t *testing.T) {
+	clock := newFakeClock()
+	l := NewLimiter(BucketRule{Key: "/auth", FillInterval: time.Second, Capacity: 1, Quantum: 1, PerClient: true}).(*Limiter)
+	l.now = clock.now
+
+	if r, _ := l.Allow("/auth", "1.1.1.1"); !r.Allowed {
+		t.Fatal("first request from client A: want allowed")
+	}
+	r, _ := l.Allow("/auth", "1.1.1.1")
+	if r.Allowed || r.RetryAfter != time.Second {
+		t.Fatalf("second request from client A: got allowed=%v retryAfter=%v", r.Allowed, r.RetryAfter)
+	}
+	if r, _ := l.Allow("/auth", "2.2.2.2"); !r.Allowed {
+		t.Fatal("first request from client B: want allowed")
+	}
+	clock.advance(time.Second)
+	if r, _ := l.Allow("/auth", "1.1.1.1"); !r.Allowed {
+		t.Fatal("client A after refill: want allowed")
+	}
+}
+
+func TestLimiterRouteFallback(t *testing.T) {
+	l := NewLimiter(BucketRule{Key: AnyRoute, FillInterval: time.Second, Capacity: 1, Quantum: 1})
+
+	if _, ok := l.Allow("/api/v1/tags", "1.1.1.1"); !ok {
+		t.Fatal("route without its own rule should fall back to AnyRoute")
+	}
+	if r, _ := l.Allow("/api/v1/tags", "2.2.2.2"); r.Allowed {
+		t.Fatal("shared route bucket: second request should be limited")
+	}
+	if r, _ := l.Allow("/api/v1/articles", "1.1.1.1"); !r.Allowed {
+		t.Fatal("each route gets its own bucket from the default rule")
+	}
+	if _, ok := NewLimiter().Allow("/api/v1/tags", ""); ok {
+		t.Fatal("limiter without rules should not match")
+	}
+}
+
+func TestLimiterResetBuckets(t *testing.T) {
+	l := NewLimiter(BucketRule{Key: "/auth", FillInterval: time.Second, Capacity: 1, Quantum: 1})
+	l.Allow("/auth", "")
+	if r, _ := l.Allow("/auth", ""); r.Allowed {
+		t.Fatal("bucket should be empty before reset")
+	}
+
+	l.ResetBuckets(BucketRule{Key: "/auth", FillInterval: time.Second, Capacity: 5, Quantum: 1})
+	r, _ := l.Allow("/auth", "")
+	if !r.Allowed || r.Limit != 5 {
+		t.Fatalf("after reset: got allowed=%v limit=%d, want new bucket with capacity 5", r.Allowed, r.Limit)
+	}
+	if _, ok := l.Allow("/upload/file", ""); ok {
+		t.Fatal("reset should drop rules that are no longer configured")
+	}
+}
+
+func TestLimiterMaxBuckets(t *testing.T) {
+	l := NewLimiter(BucketRule{Key: "/auth", FillInterval: time.Hour, Capacity: 2, Quantum: 1, PerClient: true}).(*Limiter)
+	l.maxBuckets = 3
+
+	// 每个客户端的桶都只消耗了一部分，不会自然填满
+	for _, client := range []string{"a", "b", "c", "d", "e"} {
+		l.Allow("/auth", client)
+	}
+	if len(l.buckets) != 3 || l.lru.Len() != 3 {
+		t.Fatalf("got %d buckets (%d in lru), want capped at 3", len(l.buckets), l.lru.Len())
+	}
+	for _, client := range []string{"a", "b"} {
+		if _, ok := l.buckets["/auth|"+client]; ok {
+			t.Fatalf("least recently used bucket %q should be evicted", client)
+		}
+	}
+
+	// 访问 c 后 c 变为最近使用，新客户端淘汰的是 d
+	l.Allow("/auth", "c")
+	l.Allow("/auth", "f")
+	if _, ok := l.buckets["/auth|c"]; !ok {
+		t.Fatal("recently used bucket c should be kept")
+	}
+	if _, ok := l.buckets["/auth|d"]; ok {
+		t.Fatal("bucket d should be evicted")
+	}
+	if r, _ := l.Allow("/auth", "c"); r.Allowed {
+		t.Fatal("kept bucket c should keep its state: third request within capacity 2 should be limited")
+	}
+}
@@ -0,0 +1,132 @@
+package limiter
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// 默认规则的键，未单独配置的路由各自使用一个按该规则创建的桶
+const AnyRoute = "*"
+
+// 限流规则，Key 为路由路径（如 /auth），PerClient 为 true 时每个客户端单独一个桶
+type BucketRule struct {
+	Key          string
+	FillInterval time.Duration
+	Capacity     int64
+	Quantum      int64
+	PerClient    bool
+}
+
+// 一次限流判断的结果，用于输出 X-RateLimit-* 和 Retry-After 响应头
+type Result struct {
+	Allowed    bool
+	Limit      int64
+	Remaining  int64
+	RetryAfter time.Duration
+	Reset      time.Duration
+}
+
+type LimiterIface interface {
+	Allow(route, client string) (Result, bool)
+	AddBuckets(rules ...BucketRule) LimiterIface
+	ResetBuckets(rules ...BucketRule) LimiterIface
+}
+
+// 桶数量上限，达到上限时淘汰最久未使用的桶，避免按客户端创建的桶无限增长
+const maxBuckets = 10000
+
+type Limiter struct {
+	mu      sync.Mutex
+	rules   map[string]BucketRule
+	buckets map[string]*list.Element
+	// 按最近使用排序的桶，表头为最近使用的
+	lru        *list.List
+	maxBuckets int
+	now        func() time.Time
+}
+
+type bucketEntry struct {
+	key    string
+	bucket *Bucket
+}
+
+func NewLimiter(rules ...BucketRule) LimiterIface {
+	l := &Limiter{
+		rules:      make(map[string]BucketRule),
+		buckets:    make(map[string]*list.Element),
+		lru:        list.New(),
+		maxBuckets: maxBuckets,
+		now:        time.Now,
+	}
+	return l.AddBuckets(rules...)
+}
+
+func (l *Limiter) AddBuckets(rules ...BucketRule) LimiterIface {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, rule := range rules {
+		if _, ok := l.rules[rule.Key]; !ok {
+			l.rules[rule.Key] = rule
+		}
+	}
+	return l
+}
+
+// 用新的规则替换全部规则并清空已有的桶，用于配置热更新
+func (l *Limiter) ResetBuckets(rules ...BucketRule) LimiterIface {
+	l.mu.Lock()
+	l.rules = make(map[string]BucketRule)
+	l.buckets = make(map[string]*list.Element)
+	l.lru.Init()
+	l.mu.Unlock()
+	return l.AddBuckets(rules...)
+}
+
+// 对 route 上来自 client 的一次请求做限流判断，第二个返回值表示是否有规则匹配
+func (l *Limiter) Allow(route, client string) (Result, bool) {
+	bucket, ok := l.getBucket(route, client)
+	if !ok {
+		return Result{}, false
+	}
+	allowed, remaining := bucket.TakeAvailable(1)
+	result := Result{
+		Allowed:   allowed,
+		Limit:     bucket.Capacity(),
+		Remaining: remaining,
+		Reset:     bucket.ResetTime(),
+	}
+	if !allowed {
+		result.RetryAfter = bucket.WaitTime(1)
+	}
+	return result, true
+}
+
+func (l *Limiter) getBucket(route, client string) (*Bucket, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	rule, ok := l.rules[route]
+	if !ok {
+		rule, ok = l.rules[AnyRoute]
+		if !ok {
+			return nil, false
+		}
+	}
+	key := route
+	if rule.PerClient {
+		key = route + "|" + client
+	}
+	if elem, ok := l.buckets[key]; ok {
+		l.lru.MoveToFront(elem)
+		return elem.Value.(*bucketEntry).bucket, true
+	}
+	// 最久未使用的桶通常已经填满，淘汰后客户端再次请求会得到一个新的满桶
+	for len(l.buckets) >= l.maxBuckets {
+		oldest := l.lru.Back()
+		l.lru.Remove(oldest)
+		delete(l.buckets, oldest.Value.(*bucketEntry).key)
+	}
+	bucket := newBucketWithClock(rule.FillInterval, rule.Capacity, rule.Quantum, l.now)
+	l.buckets[key] = l.lru.PushFront(&bucketEntry{key: key, bucket: bucket})
+	return bucket, true
+}
@@ -6,8 +6,13 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"os"
 	"runtime"
+	"strings"
+	"sync/atomic"
 	"time"
+
+	"blog-service/pkg/trace"
 )
 
 type Level int8
@@ -16,11 +21,21 @@ type Filelds map[string]interface{}
 
 type Logger struct {
 	newLogger *log.Logger
+	writer    io.Writer
+	sinks     *[]*sink
+	level     *atomic.Int32
 	ctx       context.Context
 	fields    Filelds
 	callers   []string
 }
 
+// 额外的日志输出，只接收不低于 level 的日志
+type sink struct {
+	logger *log.Logger
+	writer io.Writer
+	level  Level
+}
+
 const (
 	LevelDebug Level = iota
 	LevelInfo
@@ -49,10 +64,70 @@ func (l Level) String() string {
 	return ""
 }
 
+// 将字符串解析为日志级别，不区分大小写
+func ParseLevel(s string) (Level, error) {
+	switch strings.ToLower(s) {
+	case "debug":
+		return LevelDebug, nil
+	case "info":
+		return LevelInfo, nil
+	case "warn", "warning":
+		return LevelWarn, nil
+	case "error":
+		return LevelError, nil
+	case "fatal":
+		return LevelFatal, nil
+	case "panic":
+		return LevelPanic, nil
+	}
+	return LevelDebug, fmt.Errorf("unknown log level: %q", s)
+}
+
 func NewLogger(w io.Writer, prefix string, flag int) *Logger {
 	//l := log.New(w, prefix, flag)
 	//return &Logger{newLogger: l}
-	return &Logger{newLogger: log.New(w, prefix, flag)} //用一行写完逻辑，比上面的代码更简洁
+	return &Logger{newLogger: log.New(w, prefix, flag), writer: w, sinks: &[]*sink{}, level: &atomic.Int32{}}
+}
+
+// 设置最低日志级别，低于该级别的日志直接丢弃；对所有克隆出的 Logger 同时生效
+func (l *Logger) SetLevel(level Level) {
+	l.level.Store(int32(level))
+}
+
+func (l *Logger) Level() Level {
+	return Level(l.level.Load())
+}
+
+// 增加一个额外的输出，只接收不低于 level 的日志，如将错误日志同时输出到标准错误
+// 应在初始化阶段调用，对所有克隆出的 Logger 同时生效
+func (l *Logger) AddSink(w io.Writer, level Level) *Logger {
+	*l.sinks = append(*l.sinks, &sink{
+		logger: log.New(w, l.newLogger.Prefix(), l.newLogger.Flags()),
+		writer: w,
+		level:  level,
+	})
+	return l
+}
+
+// 关闭底层的输出，如 lumberjack.Logger，确保日志全部落盘
+func (l *Logger) Close() error {
+	var err error
+	writers := []io.Writer{l.writer}
+	for _, s := range *l.sinks {
+		writers = append(writers, s.writer)
+	}
+	for _, w := range writers {
+		// 标准输出和标准错误不能关闭
+		if w == os.Stdout || w == os.Stderr {
+			continue
+		}
+		if c, ok := w.(io.Closer); ok {
+			if cerr := c.Close(); cerr != nil && err == nil {
+				err = cerr
+			}
+		}
+	}
+	return err
 }
 
 // 克隆 Logger 对象，返回一个新的 Logger 对象，它具有与当前 Logger 对象相同的配置，但可能有一些额外的字段或不同的上下文
@@ -114,11 +189,15 @@ func (l *Logger) WithCallersFrames() *Logger {
 // 将日志数据格式化为 JSON 格式，便于记录和传输
 func (l *Logger) JSONFormat(level Level, message string) map[string]interface{} {
 	// 创建一个新的 map，用于存储日志数据
-	data := make(Filelds, len(l.fields)+4)
+	data := make(Filelds, len(l.fields)+7)
 	// 将日志的级别转换为字符串，并存储在 map 中，键为 "level"
 	data["level"] = level.String()
-	// 将日志的上下文转换为字符串，并存储在 map 中，键为 "context"
-	data["context"] = l.ctx
+	// 从日志的上下文中提取追踪信息，便于按 trace_id/request_id 关联同一请求的日志
+	if t, ok := trace.FromContext(l.ctx); ok {
+		data["request_id"] = t.RequestID
+		data["trace_id"] = t.TraceID
+		data["span_id"] = t.SpanID
+	}
 	// 将日志的时间转换为 Unix 时间戳，并存储在 map 中，键为 "time"
 	data["time"] = time.Now().Local().UnixNano()
 	// 将日志的消息存储在 map 中，键为 "message"
@@ -137,22 +216,25 @@ func (l *Logger) JSONFormat(level Level, message string) map[string]interface{}
 }
 
 func (l *Logger) Output(level Level, message string) {
+	// Fatal 和 Panic 不受最低级别限制，保证退出或恐慌前一定留下日志
+	if level < l.Level() && level < LevelFatal {
+		return
+	}
 	body, _ := json.Marshal(l.JSONFormat(level, message)) //将日志数据格式化为 JSON 格式
 	content := string(body)                               //将 JSON 格式的日志数据转换为字符串
+	l.newLogger.Print(content)
+	for _, s := range *l.sinks {
+		if level >= s.level {
+			s.logger.Print(content)
+		}
+	}
 	switch level {
-	case LevelDebug:
-		l.newLogger.Print(content)
-	case LevelInfo:
-		l.newLogger.Print(content)
-	case LevelWarn:
-		l.newLogger.Print(content)
-	case LevelError:
-		l.newLogger.Print(content)
 	case LevelFatal:
-		l.newLogger.Print(content)
+		// 退出前关闭输出，确保日志落盘
+		_ = l.Close()
+		os.Exit(1)
 	case LevelPanic:
-		l.newLogger.Print(content)
-		//default:
+		panic(message)
 	}
 }
 
@@ -0,0 +1,182 @@
+package markdown
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRenderSanitize(t *testing.T) {
+	tests := []struct {
+		name    string
+		source  string
+		want    []string
+		notWant []string
+	}{
+		{
+			name:    "script tag",
+			source:  "hello\n\n<script>alert(1)</script>",
+			want:    []string{"<p>hello</p>"},
+			notWant: []string{"<script", "alert(1)"},
+		},
+		{
+			name:    "event handler",
+			source:  `<img src="/a.png" onerror="alert(1)"> <a href="/x" onclick="alert(1)">x</a>`,
+			want:    []string{`src="/a.png"`, `href="/x"`},
+			notWant: []string{"onerror", "onclick"},
+		},
+		{
+			name:    "javascript url in markdown link",
+			source:  "[x](javascript:alert(1))",
+			notWant: []string{"javascript:"},
+		},
+		{
+			name:    "javascript url in raw html",
+			source:  `<a href="javascript:alert(1)">x</a> <a href="JaVaScRiPt:alert(1)">y</a>`,
+			notWant: []string{"javascript:", "JaVaScRiPt:"},
+		},
+		{
+			name:    "iframe and style",
+			source:  `<iframe src="https://evil.example"></iframe><style>body{display:none}</style>`,
+			notWant: []string{"<iframe", "<style", "evil.example"},
+		},
+		{
+			name:    "style attribute outside code",
+			source:  `<p style="position:fixed">x</p>`,
+			want:    []string{"<p>x</p>"},
+			notWant: []string{"position"},
+		},
+		{
+			name:   "safe link kept",
+			source: "[x](https://example.com/a)",
+			want:   []string{`<a href="https://example.com/a" rel="nofollow">x</a>`},
+		},
+		{
+			name:    "task list checkbox",
+			source:  "- [x] done\n- [ ] todo",
+			want:    []string{`type="checkbox"`, "checked", "disabled"},
+			notWant: []string{"<script"},
+		},
+		{
+			name:    "only checkbox inputs",
+			source:  `<input type="text" value="x"><input type="checkbox">`,
+			want:    []string{`type="checkbox"`},
+			notWant: []string{`type="text"`},
+		},
+		{
+			name:   "highlighted code uses inline colors",
+			source: "```go\nfunc main() {}\n```",
+			want:   []string{"<pre", `style="color:`, "main"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := Render(tt.source)
+			if err != nil {
+				t.Fatalf("Render err: %v", err)
+			}
+			for _, s := range tt.want {
+				if !strings.Contains(res.HTML, s) {
+					t.Errorf("HTML should contain %q, got:\n%s", s, res.HTML)
+				}
+			}
+			for _, s := range tt.notWant {
+				if strings.Contains(res.HTML, s) {
+					t.Errorf("HTML should not contain %q, got:\n%s", s, res.HTML)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderHeadingIDs(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   []string
+	}{
+		{"ascii", "# Hello World", []string{`<h1 id="hello-world">`}},
+		{"chinese kept", "## 快速 开始", []string{`<h2 id="快速-开始">`}},
+		{"punctuation dropped", "# Go, 1.23!", []string{`<h1 id="go-123">`}},
+		{"duplicates numbered", "# Intro\n\n# Intro\n\n# Intro", []string{`id="intro"`, `id="intro-1"`, `id="intro-2"`}},
+		{"empty title", "# !!!", []string{`<h1 id="heading">`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := Render(tt.source)
+			if err != nil {
+				t.Fatalf("Render err: %v", err)
+			}
+			for _, s := range tt.want {
+				if !strings.Contains(res.HTML, s) {
+					t.Errorf("HTML should contain %q, got:\n%s", s, res.HTML)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderTOC(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   []*Heading
+	}{
+		{
+			name:   "no headings",
+			source: "just text",
+			want:   nil,
+		},
+		{
+			name:   "nested",
+			source: "# A\n\n## B\n\n### C\n\n## D\n\n# E",
+			want: []*Heading{
+				{Level: 1, ID: "a", Title: "A", Children: []*Heading{
+					{Level: 2, ID: "b", Title: "B", Children: []*Heading{
+						{Level: 3, ID: "c", Title: "C"},
+					}},
+					{Level: 2, ID: "d", Title: "D"},
+				}},
+				{Level: 1, ID: "e", Title: "E"},
+			},
+		},
+		{
+			name:   "skipped level attaches to nearest parent",
+			source: "## A\n\n#### B\n\n### C",
+			want: []*Heading{
+				{Level: 2, ID: "a", Title: "A", Children: []*Heading{
+					{Level: 4, ID: "b", Title: "B"},
+					{Level: 3, ID: "c", Title: "C"},
+				}},
+			},
+		},
+		{
+			name:   "inline markup flattened",
+			source: "# Use `go test` **now**",
+			want:   []*Heading{{Level: 1, ID: "use-go-test-now", Title: "Use go test now"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := Render(tt.source)
+			if err != nil {
+				t.Fatalf("Render err: %v", err)
+			}
+			if !reflect.DeepEqual(res.TOC, tt.want) {
+				t.Errorf("TOC mismatch:\ngot  %s\nwant %s", formatTOC(res.TOC), formatTOC(tt.want))
+			}
+		})
+	}
+}
+
+func formatTOC(headings []*Heading) string {
+	var parts []string
+	for _, h := range headings {
+		s := h.ID + "(" + h.Title + ")"
+		if len(h.Children) > 0 {
+			s += "[" + formatTOC(h.Children) + "]"
+		}
+		parts = append(parts, s)
+	}
+	return strings.Join(parts, " ")
+}
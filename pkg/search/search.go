@@ -0,0 +1,203 @@
+package search
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/blevesearch/bleve/v2"
+	"github.com/blevesearch/bleve/v2/analysis/analyzer/keyword"
+	"github.com/blevesearch/bleve/v2/analysis/lang/cjk"
+	"github.com/blevesearch/bleve/v2/mapping"
+	"github.com/blevesearch/bleve/v2/search/query"
+	"go.etcd.io/bbolt"
+)
+
+var ErrInvalidQuery = errors.New("invalid query")
+
+// 标签分面返回的最大标签数
+const facetSize = 10
+
+// 打开索引时等待文件锁的时长，索引被其他进程占用时尽快返回错误
+const openTimeout = "1s"
+
+// 写入索引的文章，Tags 为标签名，用于分面统计和按标签过滤
+type Document struct {
+	ID      uint32   `json:"-"`
+	Title   string   `json:"title"`
+	Desc    string   `json:"desc"`
+	Content string   `json:"content"`
+	Tags    []string `json:"tags"`
+}
+
+type Request struct {
+	// 查询语法同 bleve query string，支持 "短语"、+必须、-排除、title:字段 等
+	Query string
+	Tag   string
+	From  int
+	Size  int
+}
+
+type Hit struct {
+	ID    uint32
+	Score float64
+	// 按字段给出高亮片段，匹配词用 <mark> 包裹
+	Highlights map[string][]string
+}
+
+type Facet struct {
+	Tag   string `json:"tag"`
+	Count int    `json:"count"`
+}
+
+type Result struct {
+	Total  uint64
+	Hits   []*Hit
+	Facets []*Facet
+}
+
+type Index struct {
+	index bleve.Index
+}
+
+// 打开 path 处的索引，不存在时创建
+func Open(path string) (*Index, error) {
+	index, err := bleve.OpenUsing(path, map[string]interface{}{"bolt_timeout": openTimeout})
+	if err == bleve.ErrorIndexPathDoesNotExist {
+		index, err = bleve.New(path, newMapping())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("search: open index %s: %w", path, err)
+	}
+	return &Index{index: index}, nil
+}
+
+// 在临时目录中重建索引，fill 通过 add 写入全部文档，完成后替换 path 处的旧索引
+func Rebuild(path string, fill func(add func(*Document) error) error) error {
+	// 服务运行时持有索引的文件锁，此时重建会让服务读到被删除的旧索引
+	if _, err := os.Stat(path); err == nil {
+		index, err := Open(path)
+		if errors.Is(err, bbolt.ErrTimeout) {
+			return fmt.Errorf("search: index %s is in use, stop the server before rebuilding", path)
+		}
+		if err == nil {
+			_ = index.Close()
+		}
+	}
+	tmpPath := path + ".rebuild"
+	if err := os.RemoveAll(tmpPath); err != nil {
+		return err
+	}
+	index, err := bleve.New(tmpPath, newMapping())
+	if err != nil {
+		return fmt.Errorf("search: create index %s: %w", tmpPath, err)
+	}
+	batch := index.NewBatch()
+	err = fill(func(doc *Document) error {
+		if err := batch.Index(docID(doc.ID), doc); err != nil {
+			return err
+		}
+		if batch.Size() < 500 {
+			return nil
+		}
+		err := index.Batch(batch)
+		batch.Reset()
+		return err
+	})
+	if err == nil {
+		err = index.Batch(batch)
+	}
+	if closeErr := index.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.RemoveAll(tmpPath)
+		return err
+	}
+	if err := os.RemoveAll(path); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
+
+func (i *Index) Index(doc *Document) error {
+	return i.index.Index(docID(doc.ID), doc)
+}
+
+func (i *Index) Delete(id uint32) error {
+	return i.index.Delete(docID(id))
+}
+
+// 按相关度排序返回命中的文章，并统计命中文章的标签分布
+func (i *Index) Search(r *Request) (*Result, error) {
+	qs := bleve.NewQueryStringQuery(r.Query)
+	if _, err := qs.Parse(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidQuery, err)
+	}
+	var q query.Query = qs
+	if r.Tag != "" {
+		tagQuery := bleve.NewTermQuery(r.Tag)
+		tagQuery.SetField("tags")
+		q = bleve.NewConjunctionQuery(q, tagQuery)
+	}
+	req := bleve.NewSearchRequestOptions(q, r.Size, r.From, false)
+	req.Highlight = bleve.NewHighlightWithStyle("html")
+	req.Highlight.Fields = []string{"title", "desc", "content"}
+	req.AddFacet("tags", bleve.NewFacetRequest("tags", facetSize))
+
+	res, err := i.index.Search(req)
+	if err != nil {
+		return nil, err
+	}
+	result := &Result{Total: res.Total}
+	for _, hit := range res.Hits {
+		id, err := strconv.ParseUint(hit.ID, 10, 32)
+		if err != nil {
+			continue
+		}
+		result.Hits = append(result.Hits, &Hit{ID: uint32(id), Score: hit.Score, Highlights: hit.Fragments})
+	}
+	if facet, ok := res.Facets["tags"]; ok && facet.Terms != nil {
+		for _, term := range facet.Terms.Terms() {
+			result.Facets = append(result.Facets, &Facet{Tag: term.Term, Count: term.Count})
+		}
+	}
+	return result, nil
+}
+
+// 索引中的文档数，也用于就绪检查确认索引可读
+func (i *Index) DocCount() (uint64, error) {
+	return i.index.DocCount()
+}
+
+func (i *Index) Close() error {
+	return i.index.Close()
+}
+
+func docID(id uint32) string {
+	return strconv.FormatUint(uint64(id), 10)
+}
+
+// 标题、摘要和正文使用 cjk 分词器，中文按二元组切分；标签不分词，整体作为一个词项
+func newMapping() mapping.IndexMapping {
+	textField := bleve.NewTextFieldMapping()
+	textField.Analyzer = cjk.AnalyzerName
+	textField.Store = true
+	textField.IncludeTermVectors = true
+
+	tagField := bleve.NewTextFieldMapping()
+	tagField.Analyzer = keyword.Name
+	tagField.IncludeInAll = false
+
+	article := bleve.NewDocumentMapping()
+	article.AddFieldMappingsAt("title", textField)
+	article.AddFieldMappingsAt("desc", textField)
+	article.AddFieldMappingsAt("content", textField)
+	article.AddFieldMappingsAt("tags", tagField)
+
+	indexMapping := bleve.NewIndexMapping()
+	indexMapping.DefaultMapping = article
+	indexMapping.DefaultAnalyzer = cjk.AnalyzerName
+	return indexMapping
+}
@@ -1,24 +1,51 @@
 package setting
 
-import "time"
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+)
 
 type ServerSettingS struct {
-	RunMode      string
-	HttpPort     string
-	ReadTimeout  time.Duration
-	WriteTimeout time.Duration
+	RunMode         string        `validate:"required,oneof=debug release test"`
+	HttpPort        string        `validate:"required,numeric"`
+	ReadTimeout     time.Duration `validate:"gt=0"`
+	WriteTimeout    time.Duration `validate:"gt=0"`
+	ShutdownTimeout time.Duration `validate:"gt=0"`
+	ShutdownDelay   time.Duration `validate:"gte=0"`
 }
 
 type AppSettingS struct {
-	DefaultPageSize int
-	MaxPageSize     int
-	LogSavePath     string
-	LogFileName     string
-	LogFileExt      string
-	//UploadSavePath       string
-	//UploadServerUrl      string
-	//UploadImageMaxSize   int
-	//UploadImageAllowExts []string
+	DefaultPageSize      int      `validate:"gt=0"`
+	MaxPageSize          int      `validate:"gtefield=DefaultPageSize"`
+	UploadSavePath       string   `validate:"required"`
+	UploadServerUrl      string   `validate:"required,url"`
+	UploadImageMaxSize   int      `validate:"gt=0"`
+	UploadImageAllowExts []string `validate:"min=1,dive,startswith=."`
+}
+
+type LogSettingS struct {
+	Level              string `validate:"oneof=debug info warn warning error fatal panic"`
+	SavePath           string `validate:"required"`
+	FileName           string `validate:"required"`
+	FileExt            string
+	MaxSize            int `validate:"gt=0"`
+	MaxAge             int `validate:"gte=0"`
+	MaxBackups         int `validate:"gte=0"`
+	Compress           bool
+	Sinks              []LogSinkSettingS `validate:"dive"`
+	AccessLogSkipPaths []string
+}
+
+type LogSinkSettingS struct {
+	Output string `validate:"required"`
+	Level  string `validate:"oneof=debug info warn warning error fatal panic"`
 }
 
 type DatabaseSettingS struct {
@@ -26,18 +53,146 @@ type DatabaseSettingS struct {
 	UserName     string
 	Password     string
 	Host         string
-	DBName       string
+	DBName       string `validate:"required"`
 	TablePrefix  string
 	Charset      string
 	ParseTime    bool
-	MaxIdleConns int
-	MaxOpenConns int
+	MaxIdleConns int `validate:"gte=0"`
+	MaxOpenConns int `validate:"gt=0"`
+}
+
+// 数据库方言，由 DatabaseSettingS.Dialect 归一化得到
+const (
+	DialectMySQL    = "mysql"
+	DialectPostgres = "postgres"
+	DialectSQLite   = "sqlite"
+)
+
+// DBType 不区分大小写，为空时默认使用 mysql，postgresql、sqlite3 为别名，不支持的类型返回空字符串
+func (d *DatabaseSettingS) Dialect() string {
+	switch strings.ToLower(d.DBType) {
+	case "", "mysql":
+		return DialectMySQL
+	case "postgres", "postgresql":
+		return DialectPostgres
+	case "sqlite", "sqlite3":
+		return DialectSQLite
+	}
+	return ""
+}
+
+// sqlite 以外的数据库需要连接地址和用户名
+func (d *DatabaseSettingS) Validate() error {
+	var errs []error
+	dialect := d.Dialect()
+	if dialect == "" {
+		errs = append(errs, fmt.Errorf("DBType: must be one of [mysql postgres postgresql sqlite sqlite3], got %q", d.DBType))
+	}
+	if dialect != DialectSQLite {
+		if d.Host == "" {
+			errs = append(errs, fmt.Errorf("Host: required for %s", d.DBType))
+		}
+		if d.UserName == "" {
+			errs = append(errs, fmt.Errorf("UserName: required for %s", d.DBType))
+		}
+	}
+	if d.MaxIdleConns > d.MaxOpenConns {
+		errs = append(errs, fmt.Errorf("MaxIdleConns: must not exceed MaxOpenConns (%d > %d)", d.MaxIdleConns, d.MaxOpenConns))
+	}
+	return errors.Join(errs...)
+}
+
+type JWTSettingS struct {
+	Secret string        `validate:"required"`
+	Issuer string        `validate:"required"`
+	Expire time.Duration `validate:"gt=0"`
+}
+
+type LimiterSettingS struct {
+	Rules []LimiterRuleS `validate:"dive"`
+}
+
+type LimiterRuleS struct {
+	Key          string        `validate:"required"`
+	FillInterval time.Duration `validate:"gt=0"`
+	Capacity     int64         `validate:"gt=0"`
+	Quantum      int64         `validate:"gt=0"`
+	PerClient    bool
+}
+
+// 站点信息，用于生成订阅源等面向读者的内容
+type SiteSettingS struct {
+	Title       string `validate:"required"`
+	Description string
+	URL         string `validate:"required,url"`
+	Author      string
+	FeedSize    int `validate:"gt=0"`
+}
+
+type SearchSettingS struct {
+	IndexPath string `validate:"required"`
 }
 
-func (s *Setting) ReadSection(k string, v interface{}) error {
-	err := s.vp.UnmarshalKey(k, v)
+type SchedulerSettingS struct {
+	PublishInterval time.Duration `validate:"gt=0"`
+}
+
+// 读取配置段到 p 中，如 &global.ServerSetting，读取方通过 p.Load() 获取配置
+// 读取过的配置段会在配置文件变化时自动重新读取，新配置整体替换旧配置，读取方不会看到只更新了一半的配置
+func ReadSection[T any](s *Setting, k string, p *atomic.Pointer[T]) error {
+	mu.Lock()
+	defer mu.Unlock()
+	sec := atomicSection[T]{key: k, ptr: p}
+	v, err := sec.decode(s)
 	if err != nil {
 		return err
 	}
+	sec.store(v)
+	if _, ok := sections[k]; !ok {
+		sections[k] = sec
+	}
 	return nil
 }
+
+// 解码到 v 指向的新结构体
+func (s *Setting) decodeSection(k string, v interface{}) error {
+	// AllSettings 会逐个键读取，环境变量覆盖的值也包含在内，UnmarshalKey 则会忽略嵌套键的环境变量
+	input := s.vp.AllSettings()[strings.ToLower(k)]
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		Result:           v,
+		WeaklyTypedInput: true,
+		DecodeHook: mapstructure.ComposeDecodeHookFunc(
+			durationHook,
+			mapstructure.StringToSliceHookFunc(","),
+		),
+	})
+	if err != nil {
+		return err
+	}
+	if err := decoder.Decode(input); err != nil {
+		return fmt.Errorf("setting: read section %s: %w", k, err)
+	}
+	return nil
+}
+
+// 时长既可以写成 60s、1m30s 这样的字符串，也可以写成数字，数字按秒计算
+func durationHook(from reflect.Type, to reflect.Type, data interface{}) (interface{}, error) {
+	if to != reflect.TypeOf(time.Duration(0)) {
+		return data, nil
+	}
+	switch v := data.(type) {
+	case string:
+		v = strings.TrimSpace(v)
+		if n, err := strconv.ParseFloat(v, 64); err == nil {
+			return time.Duration(n * float64(time.Second)), nil
+		}
+		return time.ParseDuration(v)
+	case int:
+		return time.Duration(v) * time.Second, nil
+	case int64:
+		return time.Duration(v) * time.Second, nil
+	case float64:
+		return time.Duration(v * float64(time.Second)), nil
+	}
+	return data, nil
+}
@@ -1,19 +1,150 @@
 package setting
 
-import "github.com/spf13/viper"
+import (
+	"log"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/viper"
+)
+
+// 环境变量前缀，如 BLOG_DATABASE_PASSWORD 覆盖 Database.Password
+const envPrefix = "BLOG"
 
 type Setting struct {
 	vp *viper.Viper
 }
 
-func NewSetting() (*Setting, error) {
+// configs 为配置文件所在目录或配置文件路径，未指定时使用 configs/config.yaml
+func NewSetting(configs ...string) (*Setting, error) {
 	vp := viper.New()
 	vp.SetConfigName("config")
-	vp.AddConfigPath("configs/")
 	vp.SetConfigType("yaml")
+	if len(configs) == 0 {
+		configs = []string{"configs/"}
+	}
+	for _, config := range configs {
+		if config == "" {
+			continue
+		}
+		switch filepath.Ext(config) {
+		case ".yaml", ".yml":
+			vp.SetConfigFile(config)
+		default:
+			vp.AddConfigPath(config)
+		}
+	}
+	vp.SetEnvPrefix(envPrefix)
+	vp.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	vp.AutomaticEnv()
 	err := vp.ReadInConfig()
 	if err != nil {
 		return nil, err
 	}
-	return &Setting{vp}, nil
+
+	s := &Setting{vp}
+	s.WatchSettingChange()
+	return s, nil
+}
+
+var (
+	mu       sync.Mutex
+	sections = make(map[string]section)
+	handlers []func()
+)
+
+// 已读取的配置段，load 和 store 的值为配置结构体指针
+type section interface {
+	decode(s *Setting) (interface{}, error)
+	load() interface{}
+	store(v interface{})
+}
+
+type atomicSection[T any] struct {
+	key string
+	ptr *atomic.Pointer[T]
+}
+
+func (a atomicSection[T]) decode(s *Setting) (interface{}, error) {
+	v := new(T)
+	if err := s.decodeSection(a.key, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func (a atomicSection[T]) load() interface{} {
+	return a.ptr.Load()
+}
+
+func (a atomicSection[T]) store(v interface{}) {
+	a.ptr.Store(v.(*T))
+}
+
+// 注册配置变更回调，配置文件修改并重新读取所有配置段后按注册顺序调用
+func OnChange(fn func()) {
+	mu.Lock()
+	defer mu.Unlock()
+	handlers = append(handlers, fn)
+}
+
+// 监听配置文件变化，变化时重新读取所有已读取过的配置段
+func (s *Setting) WatchSettingChange() {
+	s.vp.OnConfigChange(func(in fsnotify.Event) {
+		if err := s.ReloadAllSection(); err != nil {
+			log.Printf("setting.ReloadAllSection err: %v", err)
+			return
+		}
+		mu.Lock()
+		fns := append([]func(){}, handlers...)
+		mu.Unlock()
+		for _, fn := range fns {
+			fn()
+		}
+	})
+	s.vp.WatchConfig()
+}
+
+// 重新读取所有配置段，全部解码并校验通过后才替换，否则保留原有配置
+// 内容没有变化的配置段保留原有指针，回调可以比较指针判断配置段是否变化
+func (s *Setting) ReloadAllSection() error {
+	mu.Lock()
+	defer mu.Unlock()
+	decoded := make(map[string]interface{}, len(sections))
+	var errs ValidationErrors
+	for k, sec := range sections {
+		v, err := sec.decode(s)
+		if err != nil {
+			return err
+		}
+		errs = append(errs, validateSection(k, v)...)
+		decoded[k] = v
+	}
+	if len(errs) > 0 {
+		return errs.sorted()
+	}
+	for k, v := range decoded {
+		if !reflect.DeepEqual(sections[k].load(), v) {
+			sections[k].store(v)
+		}
+	}
+	return nil
+}
+
+// 校验所有已读取的配置段，一次性返回全部问题
+func (s *Setting) Validate() error {
+	mu.Lock()
+	defer mu.Unlock()
+	var errs ValidationErrors
+	for k, sec := range sections {
+		errs = append(errs, validateSection(k, sec.load())...)
+	}
+	if len(errs) > 0 {
+		return errs.sorted()
+	}
+	return nil
 }
@@ -0,0 +1,325 @@
+package setting
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+const validConfig = `
+Server:
+  RunMode: debug
+  HttpPort: 8000
+  ReadTimeout: 60
+  WriteTimeout: 1m30s
+  ShutdownTimeout: 2.5
+  ShutdownDelay: 0
+Database:
+  DBType: sqlite
+  DBName: blog.db
+  MaxIdleConns: 5
+  MaxOpenConns: 10
+`
+
+// 不启动文件监听，测试中显式调用 reload 重新读取
+func newTestSetting(t *testing.T, config string) (*Setting, func(string)) {
+	t.Helper()
+	mu.Lock()
+	sections = make(map[string]section)
+	mu.Unlock()
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	write := func(config string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(config), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(config)
+	vp := viper.New()
+	vp.SetConfigFile(path)
+	if err := vp.ReadInConfig(); err != nil {
+		t.Fatal(err)
+	}
+	s := &Setting{vp}
+	reload := func(config string) {
+		t.Helper()
+		write(config)
+		if err := vp.ReadInConfig(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return s, reload
+}
+
+func TestDurationHook(t *testing.T) {
+	tests := []struct {
+		in      interface{}
+		want    time.Duration
+		wantErr bool
+	}{
+		{in: 60, want: 60 * time.Second},
+		{in: int64(5), want: 5 * time.Second},
+		{in: 1.5, want: 1500 * time.Millisecond},
+		{in: "90", want: 90 * time.Second},
+		{in: " 0.5 ", want: 500 * time.Millisecond},
+		{in: "90s", want: 90 * time.Second},
+		{in: "1m30s", want: 90 * time.Second},
+		{in: "250ms", want: 250 * time.Millisecond},
+		{in: "soon", wantErr: true},
+		{in: "10 minutes", wantErr: true},
+	}
+	to := reflect.TypeOf(time.Duration(0))
+	for _, tt := range tests {
+		got, err := durationHook(reflect.TypeOf(tt.in), to, tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("durationHook(%#v) = %v, want error", tt.in, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("durationHook(%#v) err: %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("durationHook(%#v) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+
+	// 目标不是时长时原样返回
+	if got, err := durationHook(reflect.TypeOf(""), reflect.TypeOf(""), "90s"); err != nil || got != "90s" {
+		t.Errorf("durationHook on string = %v, %v, want 90s", got, err)
+	}
+}
+
+func TestReadSection(t *testing.T) {
+	s, _ := newTestSetting(t, validConfig)
+	var server atomic.Pointer[ServerSettingS]
+	if err := ReadSection(s, "Server", &server); err != nil {
+		t.Fatalf("ReadSection err: %v", err)
+	}
+	want := ServerSettingS{
+		RunMode:         "debug",
+		HttpPort:        "8000",
+		ReadTimeout:     60 * time.Second,
+		WriteTimeout:    90 * time.Second,
+		ShutdownTimeout: 2500 * time.Millisecond,
+	}
+	if got := *server.Load(); got != want {
+		t.Errorf("Server = %+v, want %+v", got, want)
+	}
+	if err := s.Validate(); err != nil {
+		t.Errorf("Validate err: %v", err)
+	}
+}
+
+func TestReadSectionInvalidDuration(t *testing.T) {
+	s, _ := newTestSetting(t, "Server:\n  ReadTimeout: soon\n")
+	var server atomic.Pointer[ServerSettingS]
+	if err := ReadSection(s, "Server", &server); err == nil {
+		t.Fatal("ReadSection should reject an invalid duration")
+	}
+	if server.Load() != nil {
+		t.Error("section should not be stored on decode error")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		fields []string
+	}{
+		{
+			name: "server",
+			config: `
+Server:
+  RunMode: prod
+  HttpPort: abc
+  ReadTimeout: 0
+  WriteTimeout: 60
+  ShutdownTimeout: 1
+  ShutdownDelay: -1
+`,
+			fields: []string{"Server.HttpPort", "Server.ReadTimeout", "Server.RunMode", "Server.ShutdownDelay"},
+		},
+		{
+			name: "database needs host and user",
+			config: `
+Database:
+  DBType: mysql
+  DBName: blog
+  MaxIdleConns: 20
+  MaxOpenConns: 10
+`,
+			fields: []string{"Database.Host", "Database.MaxIdleConns", "Database.UserName"},
+		},
+		{
+			name: "database unknown type",
+			config: `
+Database:
+  DBType: oracle
+  MaxOpenConns: 0
+`,
+			fields: []string{"Database.DBName", "Database.DBType", "Database.Host", "Database.MaxOpenConns", "Database.UserName"},
+		},
+		{
+			name: "empty type means mysql",
+			config: `
+Database:
+  DBName: blog
+  MaxOpenConns: 1
+`,
+			fields: []string{"Database.Host", "Database.UserName"},
+		},
+		{
+			name: "type is case insensitive",
+			config: `
+Database:
+  DBType: SQLite
+  DBName: blog.db
+  MaxOpenConns: 1
+`,
+		},
+		{
+			name: "sqlite needs no host",
+			config: `
+Database:
+  DBType: sqlite3
+  DBName: blog.db
+  MaxOpenConns: 1
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _ := newTestSetting(t, tt.config)
+			var server atomic.Pointer[ServerSettingS]
+			var database atomic.Pointer[DatabaseSettingS]
+			if _, ok := s.vp.AllSettings()["server"]; ok {
+				if err := ReadSection(s, "Server", &server); err != nil {
+					t.Fatalf("ReadSection err: %v", err)
+				}
+			}
+			if _, ok := s.vp.AllSettings()["database"]; ok {
+				if err := ReadSection(s, "Database", &database); err != nil {
+					t.Fatalf("ReadSection err: %v", err)
+				}
+			}
+
+			err := s.Validate()
+			if len(tt.fields) == 0 {
+				if err != nil {
+					t.Fatalf("Validate err: %v", err)
+				}
+				return
+			}
+			var errs ValidationErrors
+			if !errors.As(err, &errs) {
+				t.Fatalf("Validate err = %v, want ValidationErrors", err)
+			}
+			var fields []string
+			for _, e := range errs {
+				fields = append(fields, e.Field)
+			}
+			if !reflect.DeepEqual(fields, tt.fields) {
+				t.Errorf("fields = %v, want %v\n%v", fields, tt.fields, err)
+			}
+		})
+	}
+}
+
+func TestDatabaseDialect(t *testing.T) {
+	tests := map[string]string{
+		"":           DialectMySQL,
+		"mysql":      DialectMySQL,
+		"MySQL":      DialectMySQL,
+		"postgresql": DialectPostgres,
+		"Postgres":   DialectPostgres,
+		"sqlite3":    DialectSQLite,
+		"SQLite":     DialectSQLite,
+		"oracle":     "",
+	}
+	for dbType, want := range tests {
+		d := &DatabaseSettingS{DBType: dbType}
+		if got := d.Dialect(); got != want {
+			t.Errorf("Dialect(%q) = %q, want %q", dbType, got, want)
+		}
+	}
+}
+
+func TestReloadAllSection(t *testing.T) {
+	s, reload := newTestSetting(t, validConfig)
+	var server atomic.Pointer[ServerSettingS]
+	var database atomic.Pointer[DatabaseSettingS]
+	if err := ReadSection(s, "Server", &server); err != nil {
+		t.Fatal(err)
+	}
+	if err := ReadSection(s, "Database", &database); err != nil {
+		t.Fatal(err)
+	}
+	oldServer, oldDatabase := server.Load(), database.Load()
+
+	// 任何一个配置段无效时都不替换，包括有效的配置段
+	reload(`
+Server:
+  RunMode: release
+  HttpPort: 8000
+  ReadTimeout: 60
+  WriteTimeout: 1m30s
+  ShutdownTimeout: 2.5
+Database:
+  DBType: mysql
+  DBName: blog.db
+  MaxIdleConns: 5
+  MaxOpenConns: 10
+`)
+	err := s.ReloadAllSection()
+	var errs ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("ReloadAllSection err = %v, want ValidationErrors", err)
+	}
+	if server.Load() != oldServer || database.Load() != oldDatabase {
+		t.Fatal("invalid reload should keep the old sections")
+	}
+
+	// 解码失败同样保留原有配置
+	reload("Server:\n  ReadTimeout: soon\n")
+	if err := s.ReloadAllSection(); err == nil {
+		t.Fatal("ReloadAllSection should reject an invalid duration")
+	}
+	if server.Load() != oldServer {
+		t.Fatal("failed decode should keep the old section")
+	}
+
+	// 只替换发生变化的配置段
+	reload(validConfig + "\n")
+	if err := s.ReloadAllSection(); err != nil {
+		t.Fatalf("ReloadAllSection err: %v", err)
+	}
+	if server.Load() != oldServer || database.Load() != oldDatabase {
+		t.Fatal("unchanged sections should keep their pointers")
+	}
+
+	reload(strings.Replace(validConfig, "HttpPort: 8000", "HttpPort: 9000", 1))
+	if err := s.ReloadAllSection(); err != nil {
+		t.Fatalf("ReloadAllSection err: %v", err)
+	}
+	if server.Load() == oldServer {
+		t.Fatal("changed section should be replaced")
+	}
+	if got := server.Load().HttpPort; got != "9000" {
+		t.Errorf("HttpPort = %s, want 9000", got)
+	}
+	if database.Load() != oldDatabase {
+		t.Error("unchanged Database section should keep its pointer")
+	}
+}
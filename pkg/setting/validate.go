@@ -0,0 +1,98 @@
+package setting
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+var validate = validator.New(validator.WithRequiredStructEnabled())
+
+// 配置段可以实现 Validator 补充 validate 标签表达不了的规则
+type Validator interface {
+	Validate() error
+}
+
+// 单个配置项的问题，Field 形如 Server.HttpPort
+type ValidationError struct {
+	Field   string
+	Message string
+}
+
+func (e ValidationError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+type ValidationErrors []ValidationError
+
+func (es ValidationErrors) Error() string {
+	msgs := make([]string, 0, len(es))
+	for _, e := range es {
+		msgs = append(msgs, "  "+e.Error())
+	}
+	return fmt.Sprintf("invalid configuration (%d problems):\n%s", len(es), strings.Join(msgs, "\n"))
+}
+
+func (es ValidationErrors) sorted() ValidationErrors {
+	sort.SliceStable(es, func(i, j int) bool { return es[i].Field < es[j].Field })
+	return es
+}
+
+// 校验一个配置段，v 为配置结构体指针
+func validateSection(k string, v interface{}) ValidationErrors {
+	if reflect.ValueOf(v).IsNil() {
+		return ValidationErrors{{Field: k, Message: "section is missing"}}
+	}
+	var errs ValidationErrors
+	if err := validate.Struct(v); err != nil {
+		fieldErrs, ok := err.(validator.ValidationErrors)
+		if !ok {
+			return ValidationErrors{{Field: k, Message: err.Error()}}
+		}
+		for _, fe := range fieldErrs {
+			// Namespace 形如 ServerSettingS.HttpPort，替换为配置段名
+			field := fe.Namespace()
+			if i := strings.Index(field, "."); i >= 0 {
+				field = k + field[i:]
+			}
+			errs = append(errs, ValidationError{Field: field, Message: describe(fe)})
+		}
+	}
+	if sv, ok := v.(Validator); ok {
+		if err := sv.Validate(); err != nil {
+			// 约定错误信息形如 "Host: required"，多条用 errors.Join 合并
+			for _, msg := range strings.Split(err.Error(), "\n") {
+				field := k
+				if name, rest, ok := strings.Cut(msg, ": "); ok && !strings.ContainsAny(name, " .") {
+					field, msg = k+"."+name, rest
+				}
+				errs = append(errs, ValidationError{Field: field, Message: msg})
+			}
+		}
+	}
+	return errs
+}
+
+func describe(fe validator.FieldError) string {
+	value := fmt.Sprintf("%v", fe.Value())
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "oneof":
+		return fmt.Sprintf("must be one of [%s], got %q", fe.Param(), value)
+	case "gt", "gte", "min":
+		return fmt.Sprintf("must be %s %s, got %s", map[string]string{"gt": ">", "gte": ">=", "min": ">="}[fe.Tag()], fe.Param(), value)
+	case "gtefield":
+		return fmt.Sprintf("must be >= %s, got %s", fe.Param(), value)
+	case "numeric":
+		return fmt.Sprintf("must be numeric, got %q", value)
+	case "url":
+		return fmt.Sprintf("must be a URL, got %q", value)
+	case "startswith":
+		return fmt.Sprintf("must start with %q, got %q", fe.Param(), value)
+	}
+	return fmt.Sprintf("failed %q check, got %q", fe.Tag(), value)
+}
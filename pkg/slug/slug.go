@@ -0,0 +1,63 @@
+package slug
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/mozillazg/go-pinyin"
+	"golang.org/x/text/unicode/norm"
+)
+
+// 生成的 slug 的最大长度，为去重时追加的序号留出空间
+const MaxLength = 80
+
+var pinyinArgs = pinyin.NewArgs()
+
+// 将标题转换为 slug：ASCII 字母和数字转为小写，带变音符号的拉丁字母去掉变音符号，汉字转为不带声调的拼音，
+// 其余字符作为单词分隔，单词之间以连字符连接，超出 MaxLength 时在单词边界截断
+// 例如 "Go 语言入门" 转换为 "go-yu-yan-ru-men"，无法转换时返回空字符串
+func Make(s string) string {
+	var words []string
+	var word strings.Builder
+	flush := func() {
+		if word.Len() > 0 {
+			words = append(words, word.String())
+			word.Reset()
+		}
+	}
+	// 分解后 "é" 变为 "e" 和组合用变音符号，后者直接跳过
+	for _, r := range norm.NFD.String(s) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+		case r <= unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			word.WriteRune(unicode.ToLower(r))
+		// 撇号不分隔单词，"Go's" 转换为 "gos"
+		case r == '\'' || r == '’':
+		case unicode.Is(unicode.Han, r):
+			flush()
+			if py := pinyin.SinglePinyin(r, pinyinArgs); len(py) > 0 {
+				words = append(words, py[0])
+			}
+		default:
+			flush()
+		}
+	}
+	flush()
+
+	var b strings.Builder
+	for _, w := range words {
+		if b.Len() == 0 {
+			if len(w) > MaxLength {
+				w = w[:MaxLength]
+			}
+			b.WriteString(w)
+			continue
+		}
+		if b.Len()+1+len(w) > MaxLength {
+			break
+		}
+		b.WriteByte('-')
+		b.WriteString(w)
+	}
+	return b.String()
+}
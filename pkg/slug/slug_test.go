@@ -0,0 +1,72 @@
+package slug
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMake(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"ascii", "Hello World", "hello-world"},
+		{"digits", "Go 1.23 Release", "go-1-23-release"},
+		{"pinyin", "新标题", "xin-biao-ti"},
+		{"pinyin without tones", "语言入门", "yu-yan-ru-men"},
+		{"mixed cjk and ascii", "Go 语言入门", "go-yu-yan-ru-men"},
+		{"cjk adjacent to ascii", "Go语言", "go-yu-yan"},
+		{"fullwidth punctuation", "你好，世界 Café", "ni-hao-shi-jie-cafe"},
+		{"diacritics removed", "Crème Brûlée", "creme-brulee"},
+		{"punctuation collapsed", "  --Hello,,,   World!!  ", "hello-world"},
+		{"symbols as separators", "a/b_c+d&e", "a-b-c-d-e"},
+		{"apostrophe dropped", "Go's Types", "gos-types"},
+		{"curly apostrophe dropped", "Don’t Panic", "dont-panic"},
+		{"emoji dropped", "🚀 Launch 🚀", "launch"},
+		{"only punctuation", "!!! ???", ""},
+		{"empty", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Make(tt.in); got != tt.want {
+				t.Errorf("Make(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMakeMaxLength(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{
+			name: "cut at word boundary",
+			in:   strings.Repeat("abcdefghi ", 10),
+			want: strings.TrimSuffix(strings.Repeat("abcdefghi-", 8), "-"),
+		},
+		{
+			name: "long first word truncated",
+			in:   strings.Repeat("x", MaxLength+10) + " tail",
+			want: strings.Repeat("x", MaxLength),
+		},
+		{
+			name: "pinyin cut at syllable",
+			in:   strings.Repeat("中文", 20),
+			want: strings.TrimSuffix(strings.Repeat("zhong-wen-", 8), "-"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Make(tt.in)
+			if got != tt.want {
+				t.Errorf("Make(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+			if len(got) > MaxLength {
+				t.Errorf("len(Make(%q)) = %d, want <= %d", tt.in, len(got), MaxLength)
+			}
+		})
+	}
+}
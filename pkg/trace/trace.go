@@ -0,0 +1,92 @@
+package trace
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// 一次请求的追踪信息，TraceID/SpanID 遵循 W3C Trace Context 规范
+type Trace struct {
+	RequestID    string
+	TraceID      string
+	SpanID       string
+	ParentSpanID string
+	Flags        string
+}
+
+type contextKey struct{}
+
+func NewContext(ctx context.Context, t Trace) context.Context {
+	return context.WithValue(ctx, contextKey{}, t)
+}
+
+func FromContext(ctx context.Context) (Trace, bool) {
+	if ctx == nil {
+		return Trace{}, false
+	}
+	t, ok := ctx.Value(contextKey{}).(Trace)
+	return t, ok
+}
+
+// 生成 traceparent 请求头，格式为 00-<trace-id>-<span-id>-<flags>
+func (t Trace) Traceparent() string {
+	flags := t.Flags
+	if flags == "" {
+		flags = "01"
+	}
+	return fmt.Sprintf("00-%s-%s-%s", t.TraceID, t.SpanID, flags)
+}
+
+// 解析上游传入的 traceparent，返回上游的 trace-id、parent-id 和 flags
+func ParseTraceparent(header string) (traceID, parentID, flags string, ok bool) {
+	parts := strings.Split(strings.TrimSpace(header), "-")
+	if len(parts) < 4 {
+		return "", "", "", false
+	}
+	version, traceID, parentID, flags := parts[0], parts[1], parts[2], parts[3]
+	if !isHex(version, 2) || version == "ff" || (version == "00" && len(parts) != 4) {
+		return "", "", "", false
+	}
+	if !isHex(traceID, 32) || isZero(traceID) || !isHex(parentID, 16) || isZero(parentID) || !isHex(flags, 2) {
+		return "", "", "", false
+	}
+	return traceID, parentID, flags, true
+}
+
+func NewTraceID() string {
+	return randomHex(16)
+}
+
+func NewSpanID() string {
+	return randomHex(8)
+}
+
+func NewRequestID() string {
+	return randomHex(16)
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// 规范要求小写十六进制
+func isHex(s string, n int) bool {
+	if len(s) != n {
+		return false
+	}
+	for _, c := range s {
+		if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+func isZero(s string) bool {
+	return strings.Trim(s, "0") == ""
+}
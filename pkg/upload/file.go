@@ -0,0 +1,161 @@
+package upload
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"blog-service/global"
+	"golang.org/x/sys/unix"
+)
+
+type FileType int
+
+const TypeImage FileType = iota + 1
+
+// 图片扩展名对应的内容类型，与 http.DetectContentType 的结果比较
+var imageContentTypes = map[string]string{
+	".jpg":  "image/jpeg",
+	".jpeg": "image/jpeg",
+	".png":  "image/png",
+	".gif":  "image/gif",
+	".webp": "image/webp",
+	".bmp":  "image/bmp",
+}
+
+// 获取文件扩展名，统一转为小写
+func GetFileExt(name string) string {
+	return strings.ToLower(path.Ext(name))
+}
+
+// 根据文件内容计算哈希作为文件名，相同内容只会保存一份
+func GetFileName(content []byte, ext string) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:]) + ext
+}
+
+func GetSavePath() string {
+	return global.AppSetting.Load().UploadSavePath
+}
+
+func GetServerUrl() string {
+	return global.AppSetting.Load().UploadServerUrl
+}
+
+// 检查保存目录是否不存在
+func CheckSavePath(dst string) bool {
+	_, err := os.Stat(dst)
+	return os.IsNotExist(err)
+}
+
+// 检查扩展名是否在允许的列表中
+func CheckContainExt(t FileType, name string) bool {
+	ext := GetFileExt(name)
+	switch t {
+	case TypeImage:
+		for _, allowExt := range global.AppSetting.Load().UploadImageAllowExts {
+			if strings.ToLower(allowExt) == ext {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// 按文件头部的内容判断类型，必须是图片且与扩展名一致，避免其他内容以图片扩展名上传
+func CheckContentType(t FileType, name string, content []byte) bool {
+	switch t {
+	case TypeImage:
+		contentType, ok := imageContentTypes[GetFileExt(name)]
+		return ok && http.DetectContentType(content) == contentType
+	}
+	return false
+}
+
+// multipart 边界、字段头和其他表单字段占用的空间
+const multipartOverhead = 1024 * 1024
+
+// 上传请求体的大小上限，解析表单前用于限制请求体，避免超大的请求先写入临时文件再被拒绝
+func MaxRequestSize() int64 {
+	return int64(global.AppSetting.Load().UploadImageMaxSize)*1024*1024 + multipartOverhead
+}
+
+// 检查文件大小是否超出限制，UploadImageMaxSize 单位为 MB
+func CheckMaxSize(t FileType, size int64) bool {
+	switch t {
+	case TypeImage:
+		if size > int64(global.AppSetting.Load().UploadImageMaxSize)*1024*1024 {
+			return true
+		}
+	}
+	return false
+}
+
+// 检查保存目录是否没有权限
+func CheckPermission(dst string) bool {
+	_, err := os.Stat(dst)
+	return os.IsPermission(err)
+}
+
+func CreateSavePath(dst string, perm os.FileMode) error {
+	return os.MkdirAll(dst, perm)
+}
+
+// 确认保存目录可以写入文件，只检查权限，不修改文件系统
+// 目录不存在时检查最近一级已存在的上级目录，上传时会在其中创建保存目录
+func CheckWritable(dst string) error {
+	p := dst
+	for {
+		info, err := os.Stat(p)
+		if os.IsNotExist(err) && filepath.Dir(p) != p {
+			p = filepath.Dir(p)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			return fmt.Errorf("%s is not a directory", p)
+		}
+		if err := unix.Access(p, unix.W_OK|unix.X_OK); err != nil {
+			return fmt.Errorf("%s is not writable: %w", p, err)
+		}
+		return nil
+	}
+}
+
+// 读取上传文件的全部内容，读取量不超过 limit+1 字节
+func ReadFile(file multipart.File, limit int64) ([]byte, error) {
+	return io.ReadAll(io.LimitReader(file, limit+1))
+}
+
+// 将内容写入 dst，目标文件已存在时直接复用
+// 临时文件默认只有属主可读，改为 0644 以便其他用户运行的静态文件服务读取
+func SaveFile(content []byte, dst string) error {
+	if _, err := os.Stat(dst); err == nil {
+		return nil
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(dst), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err = tmp.Write(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	if err = os.Chmod(tmp.Name(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), dst)
+}
@@ -0,0 +1,19 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"blog-service/global"
+	"blog-service/internal/service"
+)
+
+func runRebuildIndex() error {
+	svc := service.New(context.Background())
+	count, err := svc.RebuildSearchIndex(global.SearchSetting.Load().IndexPath)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("indexed %d articles\n", count)
+	return nil
+}
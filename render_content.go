@@ -0,0 +1,18 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"blog-service/internal/service"
+)
+
+func runRenderContent() error {
+	svc := service.New(context.Background())
+	count, err := svc.RenderAllArticleContent()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("rendered %d articles\n", count)
+	return nil
+}
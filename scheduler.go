@@ -0,0 +1,31 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"blog-service/global"
+	"blog-service/internal/service"
+)
+
+// 定时发布调度器，启动时和之后每隔 Scheduler.PublishInterval 发布到期的文章，ctx 取消后退出
+// 每次等待前重新读取间隔，配置文件修改后无需重启
+func runPublishScheduler(ctx context.Context) {
+	svc := service.New(ctx)
+	for {
+		published, err := svc.PublishDueArticles()
+		if err != nil {
+			global.Logger.Errorf("svc.PublishDueArticles err: %v", err)
+		} else if published > 0 {
+			global.Logger.Infof("scheduler published %d articles", published)
+		}
+
+		timer := time.NewTimer(global.SchedulerSetting.Load().PublishInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}